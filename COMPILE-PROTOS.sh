@@ -5,5 +5,6 @@ TMP_GOBIN=$(mktemp -d -t gnostic-grpc.XXXXXXXXXX)
 GOBIN=${TMP_GOBIN} go install ./search
 GOBIN=${TMP_GOBIN} go install -mod=mod github.com/golang/protobuf/protoc-gen-go@v1.5.2
 PATH="$TMP_GOBIN:$PATH" protoc --go_out=incompatibility/ ./incompatibility/incompatibility-report.proto
+PATH="$TMP_GOBIN:$PATH" protoc --go_out=. --go_opt=paths=source_relative ./transcoding/options.proto
 
 rm -rf "${TMP_GOBIN}"
@@ -15,7 +15,7 @@ func TestGenerateAnalysis(t *testing.T) {
 		expectedNumOASProcessed int
 	}{
 		{"falseDir", "fake", 0},
-		{"dirWSubDir", "../generator/testfiles", 6},
+		{"dirWSubDir", "../generator/testfiles", 36},
 		{"dirWMalFile", "../incompatibility/oas-examples/malformed", 0},
 		{"3Docs", "../incompatibility/oas-examples", 4},
 		{"NoOpenAPIDocs", "../utils", 0},
@@ -0,0 +1,245 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	plugins "github.com/google/gnostic/plugins"
+	surface_v1 "github.com/google/gnostic/surface"
+	"google.golang.org/genproto/googleapis/api/annotations"
+)
+
+// The specification extension of an operation that groups several operations into one RPC.
+const extensionGrpcMethod = "x-grpc-method"
+
+// groupAdditionalBindings merges operations that share one logical RPC into a single method of the model. The first
+// operation of a group becomes the RPC, the HttpRules of the other operations become its additional_bindings.
+// Operations belong to the same group if they
+//   - have the same x-grpc-method extension,
+//   - have the same operationId or
+//   - have identical signatures if MergeIdenticalSignatures is set: same HTTP method, requests and responses, and one
+//     path is a suffix of the other (e.g. "/v1/books/{book}" and "/books/{book}"). Each of those merges is reported.
+func groupAdditionalBindings(renderer *Renderer) error {
+	model := renderer.Model
+	renderer.additionalBindings = make(map[*surface_v1.Method][]*annotations.HttpRule)
+	renderer.mergedSignatures = make([]*plugins.Message, 0)
+
+	primaries := make([]*surface_v1.Method, 0)
+	aliasesOf := make(map[*surface_v1.Method][]*surface_v1.Method)
+	for _, m := range model.Methods {
+		primary := findPrimaryMethod(renderer, primaries, m)
+		if primary == nil {
+			primaries = append(primaries, m)
+			continue
+		}
+		if !isSameMessage(model, primary.ResponsesTypeName, m.ResponsesTypeName) {
+			return fmt.Errorf("the operations %s and %s share one RPC but have different responses",
+				operationKey(primary.Method, primary.Path), operationKey(m.Method, m.Path))
+		}
+		aliasesOf[primary] = append(aliasesOf[primary], m)
+		if findGrpcMethod(renderer, m) == "" && (m.Operation == "" || m.Operation != primary.Operation) {
+			text := "The operation " + operationKey(m.Method, m.Path) + " has the same signature as " +
+				operationKey(primary.Method, primary.Path) + ", it is generated as an additional binding of its RPC."
+			msg := constructInfoMessage("BINDINGS", text, []string{"paths", m.Path, strings.ToLower(m.Method)})
+			renderer.mergedSignatures = append(renderer.mergedSignatures, &msg)
+		}
+	}
+	if len(primaries) == len(model.Methods) {
+		return nil
+	}
+
+	for _, primary := range primaries {
+		for _, alias := range aliasesOf[primary] {
+			httpRule, err := renderer.getHttpRuleForMethod(alias)
+			if err != nil {
+				return err
+			}
+			httpRule.Body = renderer.getRequestBody(alias)
+			renderer.additionalBindings[primary] = append(renderer.additionalBindings[primary], httpRule)
+			mergeRequestFields(model, primary, alias)
+		}
+	}
+	for _, primary := range primaries {
+		for _, alias := range aliasesOf[primary] {
+			removeTypesOfAlias(model, primary, alias)
+		}
+	}
+	model.Methods = primaries
+	return nil
+}
+
+// findPrimaryMethod returns the method of 'primaries' that shares one RPC with 'method' or nil.
+func findPrimaryMethod(renderer *Renderer, primaries []*surface_v1.Method, method *surface_v1.Method) *surface_v1.Method {
+	grpcMethod := findGrpcMethod(renderer, method)
+	for _, primary := range primaries {
+		switch {
+		case grpcMethod != "" || findGrpcMethod(renderer, primary) != "":
+			if grpcMethod == findGrpcMethod(renderer, primary) {
+				return primary
+			}
+		case method.Operation != "" && method.Operation == primary.Operation:
+			return primary
+		case renderer.MergeIdenticalSignatures && haveIdenticalSignatures(renderer.Model, primary, method):
+			return primary
+		}
+	}
+	return nil
+}
+
+// findGrpcMethod returns the value of the x-grpc-method extension of the operation of 'method'.
+func findGrpcMethod(renderer *Renderer, method *surface_v1.Method) string {
+	operation := findOperation(renderer.Document, method.Method, method.Path)
+	if operation == nil {
+		return ""
+	}
+	grpcMethod, _ := stringExtension(operation.SpecificationExtension, extensionGrpcMethod)
+	return grpcMethod
+}
+
+// haveIdenticalSignatures returns true if 'a' and 'b' are the same operation under different paths.
+func haveIdenticalSignatures(model *surface_v1.Model, a *surface_v1.Method, b *surface_v1.Method) bool {
+	if a.Method != b.Method || a.Path == b.Path {
+		return false
+	}
+	if !strings.HasSuffix(a.Path, b.Path) && !strings.HasSuffix(b.Path, a.Path) {
+		return false
+	}
+	if a.ResponsesTypeName == "" || a.ResponsesTypeName != b.ResponsesTypeName {
+		return false
+	}
+	return isSameMessage(model, a.ParametersTypeName, b.ParametersTypeName)
+}
+
+// isSameMessage returns true if the types named 'a' and 'b' are the same type or have the same fields.
+func isSameMessage(model *surface_v1.Model, a string, b string) bool {
+	if a == b {
+		return true
+	}
+	typeA, typeB := model.TypeWithTypeName(a), model.TypeWithTypeName(b)
+	if typeA == nil || typeB == nil || len(typeA.Fields) != len(typeB.Fields) {
+		return false
+	}
+	for i, f := range typeA.Fields {
+		other := typeB.Fields[i]
+		if f.FieldName != other.FieldName || f.NativeType != other.NativeType || f.Kind != other.Kind ||
+			f.Position != other.Position {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeRequestFields adds the request fields of 'alias' that are missing in the request of 'primary', so that all
+// variables of the additional bindings can be bound to a field.
+func mergeRequestFields(model *surface_v1.Model, primary *surface_v1.Method, alias *surface_v1.Method) {
+	aliasRequest := model.TypeWithTypeName(alias.ParametersTypeName)
+	if aliasRequest == nil {
+		return
+	}
+	primaryRequest := model.TypeWithTypeName(primary.ParametersTypeName)
+	if primaryRequest == nil {
+		primary.ParametersTypeName = alias.ParametersTypeName
+		return
+	}
+	for _, f := range aliasRequest.Fields {
+		exists := false
+		for _, existing := range primaryRequest.Fields {
+			if existing.FieldName == f.FieldName {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			primaryRequest.Fields = append(primaryRequest.Fields, f)
+		}
+	}
+}
+
+// removeTypesOfAlias removes the request and response messages that have been generated for the operation of 'alias'
+// alone. Messages with the same name as a message of 'primary' (e.g. because of equal operationIds) are removed too.
+func removeTypesOfAlias(model *surface_v1.Model, primary *surface_v1.Method, alias *surface_v1.Method) {
+	keep := map[*surface_v1.Type]bool{
+		model.TypeWithTypeName(primary.ParametersTypeName): true,
+		model.TypeWithTypeName(primary.ResponsesTypeName):  true,
+	}
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		isRequest := t.TypeName == alias.ParametersTypeName
+		isResponse := t.TypeName == alias.ResponsesTypeName && alias.Operation != "" &&
+			strings.HasPrefix(t.Name, alias.Operation)
+		if keep[t] || (!isRequest && !isResponse) {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+}
+
+// mergeDuplicateTypes merges the request and response types of operations with equal operationIds, which gnostic
+// creates with the same name and groupAdditionalBindings merges into one RPC. The fields of a duplicate are added to
+// the first type of that name, if they are missing there. Other types of the same name are kept: adjustV3Model removes
+// the intermediate type of a request body (e.g. "Pet" of the request bodies next to the schema "Pet" of the
+// components) and findDuplicateSymbols reports the rest.
+func mergeDuplicateTypes(model *surface_v1.Model) {
+	operations := make(map[string]int)
+	for _, m := range model.Methods {
+		if m.Operation != "" {
+			operations[m.Operation]++
+		}
+	}
+	prefixes := make([]string, 0)
+	for _, m := range model.Methods {
+		if operations[m.Operation] > 1 {
+			prefixes = append(prefixes, m.Name, m.Operation)
+		}
+	}
+
+	typesByName := make(map[string]*surface_v1.Type)
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		first, ok := typesByName[t.Name]
+		if !ok || !hasAnyPrefix(t.Name, prefixes) {
+			if !ok {
+				typesByName[t.Name] = t
+			}
+			types = append(types, t)
+			continue
+		}
+		for _, f := range t.Fields {
+			exists := false
+			for _, existing := range first.Fields {
+				if existing.Name == f.Name {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				first.Fields = append(first.Fields, f)
+			}
+		}
+	}
+	model.Types = types
+}
+
+// hasAnyPrefix returns true if 's' starts with one of 'prefixes'.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -15,6 +15,9 @@
 package generator
 
 import (
+	"strings"
+
+	"github.com/google/gnostic-grpc/utils"
 	openapiv3 "github.com/google/gnostic/openapiv3"
 	plugins "github.com/google/gnostic/plugins"
 )
@@ -24,6 +27,8 @@ type GrpcChecker struct {
 	document *openapiv3.Document
 	// The messages that are displayed to the user with information of what is not being processed by the generator.
 	messages []*plugins.Message
+	// The names that are synthesized for the operations without operationId.
+	synthesizedNames map[*openapiv3.Operation]string
 }
 
 // Creates a new checker.
@@ -33,6 +38,10 @@ func NewGrpcChecker(document *openapiv3.Document) *GrpcChecker {
 
 // Runs the checker. It is a top-down approach.
 func (c *GrpcChecker) Run() []*plugins.Message {
+	c.synthesizedNames = make(map[*openapiv3.Operation]string)
+	for _, synthesized := range synthesizeOperationIds(c.document) {
+		c.synthesizedNames[synthesized.operation] = synthesized.name
+	}
 	c.analyzeOpenAPIDocument()
 	return c.messages
 }
@@ -45,10 +54,25 @@ func (c *GrpcChecker) analyzeOpenAPIDocument() {
 		msg := constructInfoMessage("DOCUMENTFIELDS", text, []string{f})
 		c.messages = append(c.messages, &msg)
 	}
+	c.analyzeServers()
 	c.analyzeComponents()
 	c.analyzePaths()
 }
 
+// Analyzes the servers of the document. Only the base path of the first server is considered.
+func (c *GrpcChecker) analyzeServers() {
+	servers := c.document.GetServers()
+	for i, server := range servers {
+		if i == 0 || urlBasePath(server) == urlBasePath(servers[0]) {
+			continue
+		}
+		text := "Server: '" + server.Url + "' has a different base path than the first server. Only the base path '" +
+			urlBasePath(servers[0]) + "' of the first server is considered."
+		msg := constructInfoMessage("SERVERS", text, []string{"servers", server.Url})
+		c.messages = append(c.messages, &msg)
+	}
+}
+
 // Analyzes the components of a OpenAPI description.
 func (c *GrpcChecker) analyzeComponents() {
 	components := c.document.Components
@@ -78,7 +102,7 @@ func (c *GrpcChecker) analyzeComponents() {
 	if parameters := components.GetParameters(); parameters != nil {
 		for _, pair := range parameters.AdditionalProperties {
 			parentKeys := append(currentKeys, "parameters")
-			c.analyzeParameter(pair.Value, parentKeys)
+			c.analyzeParameter(nil, pair.Value, parentKeys)
 		}
 	}
 
@@ -121,13 +145,34 @@ func (c *GrpcChecker) analyzePathItem(pair *openapiv3.NamedPathItem, parentKeys
 func (c *GrpcChecker) analyzeOperation(operation *openapiv3.Operation, parentKeys []string) {
 	currentKeys := parentKeys
 	fields := getNotSupportedOperationFields(operation)
+	key := operationKey(currentKeys[len(currentKeys)-1], currentKeys[len(currentKeys)-2])
 
 	if len(operation.OperationId) == 0 {
-		text := "One of your operations does not have an 'operationId'. gnostic-grpc might produce an incorrect output file."
+		text := "Operation: " + key + " does not have an 'operationId'. Its RPC is named " +
+			c.synthesizedNames[operation] + "."
 		msg := constructWarningMessage("OPERATION", text, currentKeys)
 		c.messages = append(c.messages, &msg)
 	}
 
+	clientStreaming, serverStreaming, err := getStreamingForOperation(operation)
+	if err != nil {
+		msg := constructWarningMessage("STREAMING", err.Error(), append(copyKeys(currentKeys), extensionGrpcStreaming))
+		c.messages = append(c.messages, &msg)
+	}
+	if serverStreaming {
+		text := "Operation: " + key + " is generated as a server-streaming RPC. gRPC-JSON transcoding " +
+			"sends the stream as a JSON array (Envoy) or as newline-delimited JSON (grpc-gateway), server-sent events " +
+			"need additional configuration of the proxy."
+		msg := constructWarningMessage("STREAMING", text, currentKeys)
+		c.messages = append(c.messages, &msg)
+	}
+	if clientStreaming {
+		text := "Operation: " + key + " is generated as a client-streaming RPC. Not every gRPC-JSON " +
+			"transcoder supports client streams, which are sent as a JSON array or as newline-delimited JSON."
+		msg := constructWarningMessage("STREAMING", text, currentKeys)
+		c.messages = append(c.messages, &msg)
+	}
+
 	for _, f := range fields {
 		text := "Field: '" + f + "' is not supported for operation: " + operation.OperationId
 		msg := constructInfoMessage("OPERATIONFIELDS", text, append(copyKeys(currentKeys), f))
@@ -136,7 +181,23 @@ func (c *GrpcChecker) analyzeOperation(operation *openapiv3.Operation, parentKey
 
 	for _, param := range operation.Parameters {
 		pKeys := append(currentKeys, "parameters")
-		c.analyzeParameter(param, pKeys)
+		c.analyzeParameter(operation, param, pKeys)
+	}
+
+	successStatusCodes := make([]string, 0)
+	for _, response := range operation.Responses.GetResponseOrReference() {
+		if strings.HasPrefix(response.Name, "2") {
+			successStatusCodes = append(successStatusCodes, response.Name)
+		}
+	}
+	if len(successStatusCodes) > 1 {
+		text := "Operation: " + key + " has several successful responses (" +
+			strings.Join(successStatusCodes, ", ") + "). The RPC returns the response with the lowest status code, or " +
+			"a oneof over all of them with the parameter responses=oneof. gRPC-JSON transcoding answers every " +
+			"successful RPC with HTTP status 200, other status codes have to be set by the proxy (e.g. with a " +
+			"forward response option of grpc-gateway)."
+		msg := constructInfoMessage("RESPONSES", text, append(copyKeys(currentKeys), "responses"))
+		c.messages = append(c.messages, &msg)
 	}
 
 	for _, response := range operation.Responses.GetResponseOrReference() {
@@ -156,12 +217,12 @@ func (c *GrpcChecker) analyzeOperation(operation *openapiv3.Operation, parentKey
 
 }
 
-// Analyzes the parameter.
-func (c *GrpcChecker) analyzeParameter(paramOrRef *openapiv3.ParameterOrReference, parentKeys []string) {
+// Analyzes the parameter of 'operation', which is nil for parameters of the components.
+func (c *GrpcChecker) analyzeParameter(operation *openapiv3.Operation, paramOrRef *openapiv3.ParameterOrReference, parentKeys []string) {
 	currentKeys := parentKeys
 
 	if parameter := paramOrRef.GetParameter(); parameter != nil {
-		fields := getNotSupportedParameterFields(parameter)
+		fields := getNotSupportedParameterFields(c.document, operation, parameter)
 		for _, f := range fields {
 			text := "Field: '" + f + "' is not supported for parameter: " + parameter.Name
 			msg := constructInfoMessage("PARAMETERFIELDS", text, append(copyKeys(currentKeys), f))
@@ -185,6 +246,7 @@ func (c *GrpcChecker) analyzeResponse(pair *openapiv3.NamedResponseOrReference,
 			c.messages = append(c.messages, &msg)
 		}
 		if content := response.Content; content != nil {
+			c.analyzeHttpBodyContent(content, "response: "+pair.Name, currentKeys)
 			for _, pair := range content.AdditionalProperties {
 				pKeys := append(currentKeys, []string{"content", pair.Name}...)
 				c.analyzeContent(pair, pKeys)
@@ -203,6 +265,7 @@ func (c *GrpcChecker) analyzeRequestBody(pair *openapiv3.NamedRequestBodyOrRefer
 			msg := constructInfoMessage("REQUESTBODYFIELDS", text, append(copyKeys(currentKeys), "required"))
 			c.messages = append(c.messages, &msg)
 		}
+		c.analyzeHttpBodyContent(requestBody.Content, "the request: "+pair.Name, currentKeys)
 		for _, pair := range requestBody.Content.AdditionalProperties {
 			pKeys := append(currentKeys, []string{"content", pair.Name}...)
 			c.analyzeContent(pair, pKeys)
@@ -210,6 +273,26 @@ func (c *GrpcChecker) analyzeRequestBody(pair *openapiv3.NamedRequestBodyOrRefer
 	}
 }
 
+// Analyzes whether the content of a request body or response is passed through as google.api.HttpBody.
+func (c *GrpcChecker) analyzeHttpBodyContent(content *openapiv3.MediaTypes, identifier string, parentKeys []string) {
+	mediaTypes := make([]string, 0)
+	for _, pair := range content.GetAdditionalProperties() {
+		mediaTypes = append(mediaTypes, pair.Name)
+	}
+	if hasOnlyHttpBodyMediaTypes(mediaTypes) {
+		text := "Field: 'content' of " + identifier + " with the media types " + strings.Join(mediaTypes, ", ") +
+			" is generated as google.api.HttpBody. Its schema is not represented inside .proto."
+		for _, mediaType := range mediaTypes {
+			if utils.IsFormMediaType(mediaType) {
+				text += " The form fields are documented in the comment of the RPC."
+				break
+			}
+		}
+		msg := constructInfoMessage("CONTENT", text, append(copyKeys(parentKeys), "content"))
+		c.messages = append(c.messages, &msg)
+	}
+}
+
 // Analyzes the content of a response.
 func (c *GrpcChecker) analyzeContent(pair *openapiv3.NamedMediaType, parentKeys []string) {
 	currentKeys := parentKeys
@@ -330,9 +413,6 @@ func getNotSupportedOpenAPIDocumentFields(document *openapiv3.Document) []string
 		return fields
 	}
 
-	if document.Servers != nil {
-		fields = append(fields, "servers")
-	}
 	if document.Security != nil {
 		fields = append(fields, "security")
 	}
@@ -346,7 +426,7 @@ func getNotSupportedOpenAPIDocumentFields(document *openapiv3.Document) []string
 }
 
 // Returns fields that the won't be considered by the plugin for parameter.
-func getNotSupportedParameterFields(parameter *openapiv3.Parameter) []string {
+func getNotSupportedParameterFields(document *openapiv3.Document, operation *openapiv3.Operation, parameter *openapiv3.Parameter) []string {
 	fields := make([]string, 0)
 	if parameter == nil {
 		return fields
@@ -360,10 +440,15 @@ func getNotSupportedParameterFields(parameter *openapiv3.Parameter) []string {
 	if parameter.AllowEmptyValue {
 		fields = append(fields, "allowEmptyValue")
 	}
-	if parameter.Style != "" {
+	// Object query parameters are mapped onto nested messages (deepObject) or flattened (exploded form), if their
+	// properties can be flattened into the request message.
+	isQuery := parameter.In == "query"
+	isObject := parameter.GetSchema().GetReference() != nil || parameter.GetSchema().GetSchema().GetType() == "object"
+	if parameter.Style != "" && !(isQuery && (parameter.Style == "form" || parameter.Style == "deepObject")) {
 		fields = append(fields, "style")
 	}
-	if parameter.Explode {
+	isDeepObject := isQuery && isObject && parameter.Style == "deepObject"
+	if parameter.Explode && !isDeepObject && !utils.IsFlattenedQueryObject(document, operation, parameter) {
 		fields = append(fields, "explode")
 	}
 	if parameter.AllowReserved {
@@ -532,15 +617,6 @@ func getNotSupportedPathItemFields(pathItem *openapiv3.PathItem) []string {
 	if pathItem == nil {
 		return fields
 	}
-	if pathItem.Head != nil {
-		fields = append(fields, "head")
-	}
-	if pathItem.Options != nil {
-		fields = append(fields, "options")
-	}
-	if pathItem.Trace != nil {
-		fields = append(fields, "trace")
-	}
 	if pathItem.Servers != nil {
 		fields = append(fields, "servers")
 	}
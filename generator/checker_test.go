@@ -15,6 +15,7 @@
 package generator
 
 import (
+	"strings"
 	"testing"
 
 	plugins "github.com/google/gnostic/plugins"
@@ -98,6 +99,167 @@ func TestFeatureCheckerOther(t *testing.T) {
 	validateKeys(t, expectedMessageKeys, messages)
 }
 
+func TestFeatureCheckerStreaming(t *testing.T) {
+	input := "testfiles/streaming.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"paths", "/books:watch", "get"},
+		{"paths", "/books:export", "get"},
+		{"paths", "/books:import", "post"},
+		{"paths", "/books:sync", "post"},
+		{"paths", "/books:sync", "post"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+	// The operations are named by HTTP method and path, which they have even without operationId.
+	for _, message := range messages {
+		if key := operationKey(message.Keys[2], message.Keys[1]); !strings.HasPrefix(message.Text, "Operation: "+key+" ") {
+			t.Errorf("Message does not name the operation %s: %s", key, message.Text)
+		}
+	}
+}
+
+func TestFeatureCheckerHttpBody(t *testing.T) {
+	input := "testfiles/httpbody.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"paths", "/uploads", "post", "requestBody", "content"},
+		{"paths", "/books/{book}/cover", "put", "parameters", "required"},
+		{"paths", "/books/{book}/cover", "put", "requestBody", "content"},
+		{"paths", "/books:export", "get", "responses", "200", "content"},
+		{"paths", "/books/{book}/pdf", "get", "parameters", "required"},
+		{"paths", "/books/{book}/pdf", "get", "responses", "200", "content"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+}
+
+func TestFeatureCheckerForms(t *testing.T) {
+	input := "testfiles/forms.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"components", "requestBodies", "AttachmentUpload", "content"},
+		{"components", "requestBodies", "AttachmentUpload", "content", "multipart/form-data", "encoding"},
+		{"paths", "/login", "post", "requestBody", "content"},
+		{"paths", "/login", "post", "requestBody", "content", "application/x-www-form-urlencoded", "schema", "required"},
+		{"paths", "/books/{book}/attachments", "post", "parameters", "required"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+}
+
+func TestFeatureCheckerOneofResponses(t *testing.T) {
+	input := "testfiles/oneofresponses.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"paths", "/books", "post", "responses"},
+		{"paths", "/books:import", "post", "responses"},
+		{"paths", "/books/{book}/pages", "get", "parameters", "required"},
+		{"paths", "/books/{book}/pages", "get", "responses"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+	for _, message := range messages {
+		if key := operationKey(message.Keys[2], message.Keys[1]); message.Code == "RESPONSES" &&
+			!strings.HasPrefix(message.Text, "Operation: "+key+" has several successful responses") {
+			t.Errorf("Message does not name the operation %s: %s", key, message.Text)
+		}
+	}
+}
+
+func TestFeatureCheckerQueryObjects(t *testing.T) {
+	input := "testfiles/queryobjects.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"paths", "/books", "get", "parameters", "style"},
+		// Exploded objects with nested properties can't be flattened into the request message.
+		{"paths", "/publishers", "get", "parameters", "explode"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+}
+
+func TestFeatureCheckerOperationIds(t *testing.T) {
+	input := "testfiles/operationids.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessages := []struct {
+		keys []string
+		name string
+	}{
+		{[]string{"paths", "/shelves/{shelf}/books", "get"}, "ListShelfBooks"},
+		{[]string{"paths", "/shelves/{shelf}/books", "post"}, "CreateShelfBook"},
+		{[]string{"paths", "/shelves/{shelf}/books/{book}", "get"}, "GetShelfBook"},
+		{[]string{"paths", "/shelves/{shelf}/books/{book}", "delete"}, "DeleteShelfBook"},
+		{[]string{"paths", "/shelves/{shelf}/books:archive", "post"}, "ArchiveShelfBooks"},
+		{[]string{"paths", "/store/inventory", "get"}, "GetStoreInventory"},
+		{[]string{"paths", "/v2/categories", "get"}, "ListV2Categories"},
+		{[]string{"paths", "/categories/", "get"}, "ListCategories2"},
+	}
+	expectedMessageKeys := make([][]string, 0)
+	for i, expected := range expectedMessages {
+		expectedMessageKeys = append(expectedMessageKeys, expected.keys)
+		if i < len(messages) && !strings.HasSuffix(messages[i].Text, "Its RPC is named "+expected.name+".") {
+			t.Errorf("Message does not contain the name %s: %s", expected.name, messages[i].Text)
+		}
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+}
+
+func TestFeatureCheckerServers(t *testing.T) {
+	input := "testfiles/servers.yaml"
+	documentv3, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+
+	checker := NewGrpcChecker(documentv3)
+	messages := checker.Run()
+	expectedMessageKeys := [][]string{
+		{"servers", "https://staging.example.com/v1"},
+		{"paths", "/books/{book}", "get", "parameters", "required"},
+		{"paths", "/legacy/books/{book}", "get", "parameters", "required"},
+	}
+	validateKeys(t, expectedMessageKeys, messages)
+}
+
 func validateKeys(t *testing.T, expectedKeys [][]string, messages []*plugins.Message) {
 	if len(expectedKeys) != len(messages) {
 		t.Errorf("Number of messages from GrpcChecker does not match expected number")
@@ -0,0 +1,400 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/search"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	surface_v1 "github.com/google/gnostic/surface"
+	"gopkg.in/yaml.v3"
+)
+
+// The number of times operations are renamed before duplicate symbols are reported as error.
+const maxRenameRounds = 5
+
+// symbolOrigin is the object of the OpenAPI document a symbol of the generated .proto file has been generated for.
+type symbolOrigin struct {
+	// The keys of the object inside the document, e.g.: ["paths", "/books", "get", "operationId"]. Empty for inline
+	// schemas that can't be located.
+	keys []string
+	// The operation of the symbol, nil for schemas. Only symbols of operations can be renamed.
+	operation *openapiv3.Operation
+}
+
+// duplicateSymbol is a name that is used for several symbols of the generated .proto file.
+type duplicateSymbol struct {
+	name    string
+	origins []*symbolOrigin
+}
+
+// findDuplicateSymbols returns the symbols that would be defined more than once by the .proto file of 'model', which
+// has been prepared by ProtoLanguageModel. Those are
+//   - operations with the same operationId that can't share one RPC, because their successful responses or their
+//     x-grpc-method extensions differ,
+//   - RPCs of one service with the same name and
+//   - messages with the same name, e.g. the request message of the operation "getBook" and the schema "GetBookRequest".
+func findDuplicateSymbols(model *surface_v1.Model, document *openapiv3.Document, servicesPerTag bool,
+	mergeSignatures bool) []*duplicateSymbol {
+	duplicates := findDuplicateOperationIds(document)
+
+	methodsByName := make(map[string][]*surface_v1.Method)
+	names := make([]string, 0)
+	for _, m := range model.Methods {
+		name := m.HandlerName
+		if servicesPerTag {
+			name = findServiceTag(document, m) + "." + name
+		}
+		if len(methodsByName[name]) == 0 {
+			names = append(names, name)
+		}
+		methodsByName[name] = append(methodsByName[name], m)
+	}
+	for _, name := range names {
+		methods := methodsByName[name]
+		duplicate := &duplicateSymbol{name: methods[0].HandlerName, origins: []*symbolOrigin{methodOrigin(document, methods[0])}}
+		for _, m := range methods[1:] {
+			if !shareOneRPC(model, document, methods[0], m, mergeSignatures) {
+				duplicate.origins = append(duplicate.origins, methodOrigin(document, m))
+			}
+		}
+		if len(duplicate.origins) > 1 {
+			duplicates = append(duplicates, duplicate)
+		}
+	}
+
+	typesByName := make(map[string][]*surface_v1.Type)
+	names = make([]string, 0)
+	for _, t := range model.Types {
+		if len(typesByName[t.TypeName]) == 0 {
+			names = append(names, t.TypeName)
+		}
+		typesByName[t.TypeName] = append(typesByName[t.TypeName], t)
+	}
+	for _, name := range names {
+		types := typesByName[name]
+		if len(types) < 2 {
+			continue
+		}
+		duplicate := &duplicateSymbol{name: name}
+		for _, t := range types {
+			duplicate.origins = append(duplicate.origins, typeOrigin(model, document, t))
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	return duplicates
+}
+
+// findDuplicateOperationIds returns the operationIds of 'document' that are used by operations which can't share one
+// RPC. Operations with equal operationIds are merged into one RPC otherwise (see groupAdditionalBindings).
+func findDuplicateOperationIds(document *openapiv3.Document) []*duplicateSymbol {
+	duplicates := make([]*duplicateSymbol, 0)
+	duplicatesById := make(map[string]*duplicateSymbol)
+	firstOperations := make(map[string]*openapiv3.Operation)
+	for _, namedPath := range document.GetPaths().GetPath() {
+		operations, methods := getValidOperations(namedPath.Value)
+		for i, operation := range operations {
+			id := operation.OperationId
+			first, ok := firstOperations[id]
+			if id == "" || !ok {
+				firstOperations[id] = operation
+				continue
+			}
+			firstMethod, _ := stringExtension(first.SpecificationExtension, extensionGrpcMethod)
+			grpcMethod, _ := stringExtension(operation.SpecificationExtension, extensionGrpcMethod)
+			switch {
+			case firstMethod != "" && firstMethod == grpcMethod:
+				// Grouped by x-grpc-method, groupAdditionalBindings reports different responses.
+				continue
+			case firstMethod == grpcMethod && proto.Equal(lowestSuccessResponse(first), lowestSuccessResponse(operation)):
+				continue
+			}
+			duplicate, ok := duplicatesById[id]
+			if !ok {
+				duplicate = &duplicateSymbol{name: id, origins: []*symbolOrigin{operationOrigin(document, first)}}
+				duplicatesById[id] = duplicate
+				duplicates = append(duplicates, duplicate)
+			}
+			duplicate.origins = append(duplicate.origins, &symbolOrigin{
+				keys:      []string{"paths", namedPath.Name, methods[i], "operationId"},
+				operation: operation,
+			})
+		}
+	}
+	return duplicates
+}
+
+// lowestSuccessResponse returns the 2xx response of 'operation' with the lowest status code or nil.
+func lowestSuccessResponse(operation *openapiv3.Operation) *openapiv3.ResponseOrReference {
+	var result *openapiv3.ResponseOrReference
+	lowest := 0
+	for _, namedResponse := range operation.GetResponses().GetResponseOrReference() {
+		statusCode, err := strconv.Atoi(namedResponse.Name)
+		if err != nil || statusCode < 200 || statusCode > 299 || (lowest != 0 && statusCode >= lowest) {
+			continue
+		}
+		lowest, result = statusCode, namedResponse.Value
+	}
+	return result
+}
+
+// shareOneRPC returns true if the methods 'a' and 'b' are merged into one RPC by groupAdditionalBindings. Identical
+// signatures are only merged if 'mergeSignatures' is set.
+func shareOneRPC(model *surface_v1.Model, document *openapiv3.Document, a *surface_v1.Method, b *surface_v1.Method,
+	mergeSignatures bool) bool {
+	grpcMethodA, grpcMethodB := "", ""
+	if operation := findOperation(document, a.Method, a.Path); operation != nil {
+		grpcMethodA, _ = stringExtension(operation.SpecificationExtension, extensionGrpcMethod)
+	}
+	if operation := findOperation(document, b.Method, b.Path); operation != nil {
+		grpcMethodB, _ = stringExtension(operation.SpecificationExtension, extensionGrpcMethod)
+	}
+	switch {
+	case grpcMethodA != "" || grpcMethodB != "":
+		return grpcMethodA == grpcMethodB
+	case a.Operation != "" && a.Operation == b.Operation:
+		return true
+	}
+	return mergeSignatures && haveIdenticalSignatures(model, a, b)
+}
+
+// methodOrigin returns the origin of the RPC of 'method', which is its operationId if it has one.
+func methodOrigin(document *openapiv3.Document, method *surface_v1.Method) *symbolOrigin {
+	operation := findOperation(document, method.Method, method.Path)
+	keys := []string{"paths", method.Path, strings.ToLower(method.Method)}
+	if operation.GetOperationId() != "" {
+		keys = append(keys, "operationId")
+	}
+	return &symbolOrigin{keys: keys, operation: operation}
+}
+
+// operationOrigin returns the origin of the operationId of 'operation'.
+func operationOrigin(document *openapiv3.Document, operation *openapiv3.Operation) *symbolOrigin {
+	for _, namedPath := range document.GetPaths().GetPath() {
+		operations, methods := getValidOperations(namedPath.Value)
+		for i, o := range operations {
+			if o == operation {
+				return &symbolOrigin{keys: []string{"paths", namedPath.Name, methods[i], "operationId"}, operation: operation}
+			}
+		}
+	}
+	return &symbolOrigin{operation: operation}
+}
+
+// typeOrigin returns the origin of the message of 't': the component schema of the same name or the operation whose
+// operationId is the longest prefix of the name of 't', like gnostic names the types of operations.
+func typeOrigin(model *surface_v1.Model, document *openapiv3.Document, t *surface_v1.Type) *symbolOrigin {
+	for _, namedSchema := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		if namedSchema.Name == t.Name {
+			return &symbolOrigin{keys: []string{"components", "schemas", t.Name}}
+		}
+	}
+	var origin *surface_v1.Method
+	for _, m := range model.Methods {
+		if m.Operation != "" && strings.HasPrefix(strings.ToLower(t.Name), strings.ToLower(m.Operation)) &&
+			(origin == nil || len(m.Operation) > len(origin.Operation)) {
+			origin = m
+		}
+	}
+	if origin == nil {
+		return &symbolOrigin{}
+	}
+	return methodOrigin(document, origin)
+}
+
+// renameDuplicateOperations renames the operations that cause 'duplicates' by appending a number to their
+// operationId. Symbols of schemas are kept, as well as the first symbol of operations only. A message is returned for
+// every renamed operation. It returns false if a duplicate can't be resolved because it isn't caused by an operation.
+func renameDuplicateOperations(document *openapiv3.Document, duplicates []*duplicateSymbol, locator *symbolLocator) ([]*plugins.Message, bool) {
+	usedIds := make(map[string]bool)
+	for _, namedPath := range document.GetPaths().GetPath() {
+		operations, _ := getValidOperations(namedPath.Value)
+		for _, operation := range operations {
+			usedIds[strings.ToLower(operation.OperationId)] = true
+		}
+	}
+
+	messages := make([]*plugins.Message, 0)
+	renamed := make(map[*openapiv3.Operation]bool)
+	for _, duplicate := range duplicates {
+		// Symbols of schemas keep their names, otherwise the first operation does.
+		keep := false
+		for _, origin := range duplicate.origins {
+			keep = keep || origin.operation == nil
+		}
+		resolved := false
+		for _, origin := range duplicate.origins {
+			switch {
+			case origin.operation == nil:
+				continue
+			case renamed[origin.operation]:
+				resolved = true
+				continue
+			case !keep:
+				keep = true
+				continue
+			}
+			operation := origin.operation
+			if operation.OperationId == "" {
+				return nil, false
+			}
+			newId := operation.OperationId
+			for i := 2; usedIds[strings.ToLower(newId)]; i++ {
+				newId = operation.OperationId + strconv.Itoa(i)
+			}
+			usedIds[strings.ToLower(newId)] = true
+			renamed[operation], resolved = true, true
+
+			others := make([]string, 0)
+			for _, other := range duplicate.origins {
+				if other != origin {
+					others = append(others, locator.describe(other))
+				}
+			}
+			messages = append(messages, &plugins.Message{
+				Code:  "DUPLICATES",
+				Level: plugins.Message_WARNING,
+				Text: "Renamed the operation " + locator.describe(origin) + " to " + newId + ", because " +
+					duplicate.name + " is also generated for " + strings.Join(others, " and ") + ".",
+				Keys: origin.keys,
+			})
+			operation.OperationId = newId
+		}
+		if !resolved {
+			return nil, false
+		}
+	}
+	return messages, true
+}
+
+// validateFileDescriptor returns an error if the .proto file 'fd' defines a symbol more than once. This can still
+// happen after findDuplicateSymbols, e.g. for enums or messages of type mappings.
+func validateFileDescriptor(renderer *Renderer, fd *dpb.FileDescriptorProto) error {
+	// The origins of the symbols are only looked up for duplicates.
+	type symbol struct {
+		name   string
+		origin func() *symbolOrigin
+	}
+	unknownOrigin := func() *symbolOrigin { return &symbolOrigin{} }
+	symbols := make([]*symbol, 0)
+	for _, message := range fd.MessageType {
+		name := message.GetName()
+		symbols = append(symbols, &symbol{name, func() *symbolOrigin {
+			if t := renderer.Model.TypeWithTypeName(name); t != nil {
+				return typeOrigin(renderer.Model, renderer.Document, t)
+			}
+			return &symbolOrigin{}
+		}})
+	}
+	for _, enum := range fd.EnumType {
+		symbols = append(symbols, &symbol{enum.GetName(), unknownOrigin})
+	}
+	for _, service := range fd.Service {
+		symbols = append(symbols, &symbol{service.GetName(), unknownOrigin})
+		for _, method := range service.Method {
+			name := method.GetName()
+			symbols = append(symbols, &symbol{service.GetName() + "." + name, func() *symbolOrigin {
+				for _, m := range renderer.Model.Methods {
+					if m.HandlerName == name {
+						return methodOrigin(renderer.Document, m)
+					}
+				}
+				return &symbolOrigin{}
+			}})
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, s := range symbols {
+		counts[s.name]++
+	}
+	duplicates := make([]*duplicateSymbol, 0)
+	duplicatesByName := make(map[string]*duplicateSymbol)
+	for _, s := range symbols {
+		if counts[s.name] < 2 {
+			continue
+		}
+		duplicate, ok := duplicatesByName[s.name]
+		if !ok {
+			duplicate = &duplicateSymbol{name: s.name}
+			duplicatesByName[s.name] = duplicate
+			duplicates = append(duplicates, duplicate)
+		}
+		duplicate.origins = append(duplicate.origins, s.origin())
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	return newDuplicateSymbolsError(duplicates, newSymbolLocator(renderer.SourceName))
+}
+
+// newDuplicateSymbolsError returns an error that lists all 'duplicates' with the locations of their origins.
+func newDuplicateSymbolsError(duplicates []*duplicateSymbol, locator *symbolLocator) error {
+	descriptions := make([]string, 0)
+	for _, duplicate := range duplicates {
+		origins := make([]string, 0)
+		for _, origin := range duplicate.origins {
+			origins = append(origins, locator.describe(origin))
+		}
+		descriptions = append(descriptions, duplicate.name+" is generated for "+strings.Join(origins, " and "))
+	}
+	return errors.New("duplicate symbols in the generated .proto file (use the parameter duplicates=rename to " +
+		"rename operations): " + strings.Join(descriptions, "; "))
+}
+
+// symbolLocator finds the origins of symbols inside the OpenAPI document.
+type symbolLocator struct {
+	sourceName string
+	// The root node of the document, nil if it can't be read.
+	node *yaml.Node
+}
+
+// newSymbolLocator reads the OpenAPI document 'sourceName'. Origins are described without line numbers if it can't
+// be read.
+func newSymbolLocator(sourceName string) *symbolLocator {
+	locator := &symbolLocator{sourceName: sourceName}
+	if node, err := search.MakeNode(sourceName); sourceName != "" && err == nil && len(node.Content) > 0 {
+		locator.node = node.Content[0]
+	}
+	return locator
+}
+
+// describe returns the JSON pointer of 'origin' and its position, e.g.:
+// "#/paths/~1books/get/operationId (bookstore.yaml:12:7)".
+func (l *symbolLocator) describe(origin *symbolOrigin) string {
+	if len(origin.keys) == 0 {
+		return "an inline schema"
+	}
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	pointer := "#"
+	for _, key := range origin.keys {
+		pointer += "/" + escaper.Replace(key)
+	}
+	if l.node == nil {
+		return pointer
+	}
+	line, column, err := search.FindKey(l.node, origin.keys...)
+	if err != nil {
+		return pointer
+	}
+	return fmt.Sprintf("%s (%s:%d:%d)", pointer, l.sourceName, line, column)
+}
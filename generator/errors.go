@@ -0,0 +1,139 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/gnostic-grpc/transcoding"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+	"google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// errorResponse is a 4xx, 5xx or default response of an operation. Only the response with the lowest status code
+// becomes the output of the RPC, error responses are documented in the comment of the RPC instead.
+type errorResponse struct {
+	// The status code of the response, e.g.: "404" or "default".
+	status string
+	// The message generated for the error schema, nil if the response has no content or a scalar schema.
+	detail *surface_v1.Type
+	// The type of the content if there is no message for it, e.g.: "string".
+	nativeType string
+}
+
+// collectErrorResponses returns the error responses of all methods of the model in the order of 'document', which
+// may be nil. It has to be called before AdjustSurfaceModel, which removes the types that hold the responses of all
+// status codes.
+func collectErrorResponses(model *surface_v1.Model, document *openapiv3.Document) map[*surface_v1.Method][]*errorResponse {
+	errorResponses := make(map[*surface_v1.Method][]*errorResponse)
+	for _, m := range model.Methods {
+		responses := model.TypeWithTypeName(m.ResponsesTypeName)
+		fieldsByStatus := make(map[string]*surface_v1.Field)
+		statuses := make([]string, 0)
+		if responses != nil {
+			for _, f := range responses.Fields {
+				fieldsByStatus[f.Name] = f
+				statuses = append(statuses, f.Name)
+			}
+		}
+		if operation := findOperation(document, m.Method, m.Path); operation != nil {
+			// Responses without content have no field in the surface model.
+			statuses = make([]string, 0)
+			for _, namedResponse := range operation.GetResponses().GetResponseOrReference() {
+				statuses = append(statuses, namedResponse.Name)
+			}
+			if operation.GetResponses().GetDefault() != nil {
+				statuses = append(statuses, "default")
+			}
+		}
+
+		// Without a successful response, the response with the lowest status code is the output of the RPC.
+		var output *surface_v1.Field
+		if responses != nil {
+			output = findLowestStatusCodeField(responses)
+		}
+		for _, status := range statuses {
+			f := fieldsByStatus[status]
+			if !isErrorStatus(status) || (f != nil && f == output) {
+				continue
+			}
+			response := &errorResponse{status: status}
+			if f != nil {
+				content := model.TypeWithTypeName(f.NativeType)
+				if content != nil && len(content.Fields) > 0 && strings.Contains(content.Fields[0].Name, "/") {
+					// OpenAPI v3: The type of the status code has one field per media type.
+					response.nativeType = content.Fields[0].NativeType
+					content = model.TypeWithTypeName(content.Fields[0].NativeType)
+				}
+				response.detail = content
+			}
+			errorResponses[m] = append(errorResponses[m], response)
+		}
+	}
+	return errorResponses
+}
+
+// findLowestStatusCodeField returns the field of 'responses' with the lowest numeric status code.
+func findLowestStatusCodeField(responses *surface_v1.Type) *surface_v1.Field {
+	var lowest *surface_v1.Field
+	lowestStatusCode := 0
+	for _, f := range responses.Fields {
+		statusCode, err := strconv.Atoi(f.Name)
+		if err == nil && (lowest == nil || statusCode < lowestStatusCode) {
+			lowest, lowestStatusCode = f, statusCode
+		}
+	}
+	return lowest
+}
+
+// isErrorStatus returns true if 'status' is the status code of an error response, e.g.: "404", "5XX" or "default".
+func isErrorStatus(status string) bool {
+	return status == "default" || strings.HasPrefix(status, "4") || strings.HasPrefix(status, "5")
+}
+
+// buildErrorComment documents the 'errorResponses' of a method, e.g.:
+//
+//	Error responses (google.rpc.Status.details):
+//	  - 404 NOT_FOUND: Error
+//	  - default UNKNOWN
+func buildErrorComment(errorResponses []*errorResponse) string {
+	if len(errorResponses) == 0 {
+		return ""
+	}
+	lines := []string{"Error responses (google.rpc.Status.details):"}
+	for _, response := range errorResponses {
+		line := "  - " + response.status + " " + grpcCodeName(response.status)
+		if response.detail != nil {
+			line += ": " + response.detail.TypeName
+		} else if response.nativeType != "" {
+			line += ": " + response.nativeType
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// grpcCodeName returns the name of the gRPC code for the HTTP status code 'status', e.g.: "NOT_FOUND" for "404".
+// Ranges like "4XX" map onto the code of "400" and "500".
+func grpcCodeName(status string) string {
+	status = strings.NewReplacer("X", "0", "x", "0").Replace(status)
+	httpStatus, err := strconv.Atoi(status)
+	if err != nil {
+		return code.Code_UNKNOWN.String()
+	}
+	return code.Code(transcoding.CodeFromHTTPStatus(httpStatus)).String()
+}
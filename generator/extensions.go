@@ -0,0 +1,323 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+	"google.golang.org/protobuf/encoding/protowire"
+	"gopkg.in/yaml.v3"
+)
+
+// The specification extensions that override the generated output for a single element of the OpenAPI description.
+const (
+	extensionName        = "x-proto-name"         // schemas, properties and operations
+	extensionType        = "x-proto-type"         // properties
+	extensionSkip        = "x-proto-skip"         // properties and operations
+	extensionFieldNumber = "x-proto-field-number" // properties
+	extensionPackage     = "x-proto-package"      // the document
+)
+
+// protoOverrides holds the values of the x-proto-* extensions of a single schema, property or operation.
+type protoOverrides struct {
+	name        string
+	protoType   string
+	skip        bool
+	fieldNumber int
+}
+
+// protoExtensions holds all x-proto-* extensions of an OpenAPI document. The surface model doesn't carry
+// specification extensions, so they are read from the document itself.
+type protoExtensions struct {
+	packageName string
+	// Keyed by the name of the component schema.
+	schemas map[string]*protoOverrides
+	// Keyed by the name of the component schema and the name of the property.
+	properties map[string]map[string]*protoOverrides
+	// Keyed by operationKey.
+	operations map[string]*protoOverrides
+}
+
+// newProtoExtensions collects the x-proto-* extensions of 'document'. 'document' may be nil.
+func newProtoExtensions(document *openapiv3.Document) *protoExtensions {
+	extensions := &protoExtensions{
+		schemas:    make(map[string]*protoOverrides),
+		properties: make(map[string]map[string]*protoOverrides),
+		operations: make(map[string]*protoOverrides),
+	}
+	if document == nil {
+		return extensions
+	}
+	extensions.packageName, _ = stringExtension(document.SpecificationExtension, extensionPackage)
+
+	for _, namedSchema := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		schema := namedSchema.GetValue().GetSchema()
+		if schema == nil {
+			continue
+		}
+		if overrides := readProtoOverrides(schema.SpecificationExtension); overrides != nil {
+			extensions.schemas[namedSchema.Name] = overrides
+		}
+		for _, namedProperty := range schema.GetProperties().GetAdditionalProperties() {
+			property := namedProperty.GetValue().GetSchema()
+			if property == nil {
+				continue
+			}
+			if overrides := readProtoOverrides(property.SpecificationExtension); overrides != nil {
+				if extensions.properties[namedSchema.Name] == nil {
+					extensions.properties[namedSchema.Name] = make(map[string]*protoOverrides)
+				}
+				extensions.properties[namedSchema.Name][namedProperty.Name] = overrides
+			}
+		}
+	}
+
+	for _, namedPathItem := range document.GetPaths().GetPath() {
+		for method, operation := range getAllOperations(namedPathItem.Value) {
+			if overrides := readProtoOverrides(operation.SpecificationExtension); overrides != nil {
+				extensions.operations[operationKey(method, namedPathItem.Name)] = overrides
+			}
+		}
+	}
+	return extensions
+}
+
+// property returns the overrides for the property 'propertyName' of the schema 'schemaName' or nil.
+func (e *protoExtensions) property(schemaName string, propertyName string) *protoOverrides {
+	return e.properties[schemaName][propertyName]
+}
+
+// operation returns the overrides for the operation that corresponds to 'method' or nil.
+func (e *protoExtensions) operation(method *surface_v1.Method) *protoOverrides {
+	return e.operations[operationKey(method.Method, method.Path)]
+}
+
+// operationKey identifies an operation by its HTTP method and path, e.g.: "GET /shelves/{shelf}".
+func operationKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// readProtoOverrides returns the x-proto-* extensions of 'extensions' or nil if there are none.
+func readProtoOverrides(extensions []*openapiv3.NamedAny) *protoOverrides {
+	overrides := &protoOverrides{}
+	found := false
+	if name, ok := stringExtension(extensions, extensionName); ok {
+		overrides.name, found = name, true
+	}
+	if protoType, ok := stringExtension(extensions, extensionType); ok {
+		overrides.protoType, found = protoType, true
+	}
+	if skip, ok := boolExtension(extensions, extensionSkip); ok {
+		overrides.skip, found = skip, true
+	}
+	if number, ok := intExtension(extensions, extensionFieldNumber); ok {
+		overrides.fieldNumber, found = number, true
+	}
+	if !found {
+		return nil
+	}
+	return overrides
+}
+
+// getAllOperations returns all operations of 'pathItem' keyed by their upper case HTTP method.
+func getAllOperations(pathItem *openapiv3.PathItem) map[string]*openapiv3.Operation {
+	operations := make(map[string]*openapiv3.Operation)
+	if pathItem == nil {
+		return operations
+	}
+	for method, operation := range map[string]*openapiv3.Operation{
+		"GET":     pathItem.Get,
+		"PUT":     pathItem.Put,
+		"POST":    pathItem.Post,
+		"DELETE":  pathItem.Delete,
+		"OPTIONS": pathItem.Options,
+		"HEAD":    pathItem.Head,
+		"PATCH":   pathItem.Patch,
+		"TRACE":   pathItem.Trace,
+	} {
+		if operation != nil {
+			operations[method] = operation
+		}
+	}
+	return operations
+}
+
+// findOperation returns the operation of 'document' for 'method' and 'path' or nil if there is no such operation.
+func findOperation(document *openapiv3.Document, method string, path string) *openapiv3.Operation {
+	for _, namedPathItem := range document.GetPaths().GetPath() {
+		if namedPathItem.Name == path {
+			return getAllOperations(namedPathItem.Value)[strings.ToUpper(method)]
+		}
+	}
+	return nil
+}
+
+// decodeExtension decodes the value of the specification extension 'name' into 'value'. It returns false if there
+// is no such extension or if the value has an unexpected type.
+func decodeExtension(extensions []*openapiv3.NamedAny, name string, value interface{}) bool {
+	for _, extension := range extensions {
+		if extension.Name != name || extension.Value == nil {
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(extension.Value.Yaml), value); err != nil {
+			log.Printf("The value of the extension %s is invalid: %s", name, err.Error())
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// stringExtension returns the value of the string extension 'name'.
+func stringExtension(extensions []*openapiv3.NamedAny, name string) (string, bool) {
+	var value string
+	ok := decodeExtension(extensions, name, &value)
+	return value, ok
+}
+
+// boolExtension returns the value of the boolean extension 'name'.
+func boolExtension(extensions []*openapiv3.NamedAny, name string) (bool, bool) {
+	var value bool
+	ok := decodeExtension(extensions, name, &value)
+	return value, ok
+}
+
+// intExtension returns the value of the integer extension 'name'.
+func intExtension(extensions []*openapiv3.NamedAny, name string) (int, bool) {
+	var value int
+	ok := decodeExtension(extensions, name, &value)
+	return value, ok
+}
+
+// applyProtoExtensions renames, retypes and removes the types, fields and methods of 'model' according to the
+// x-proto-* extensions.
+func applyProtoExtensions(model *surface_v1.Model, extensions *protoExtensions) {
+	renamedTypes := make(map[string]string)
+	for _, t := range model.Types {
+		if overrides, ok := extensions.schemas[t.Name]; ok && overrides.name != "" {
+			renamedTypes[t.TypeName] = overrides.name
+			t.TypeName = overrides.name
+		}
+
+		fields := make([]*surface_v1.Field, 0)
+		for _, f := range t.Fields {
+			overrides := extensions.property(t.Name, f.Name)
+			if overrides == nil {
+				fields = append(fields, f)
+				continue
+			}
+			if overrides.skip {
+				continue
+			}
+			if overrides.name != "" {
+				f.FieldName = overrides.name
+			}
+			if overrides.protoType != "" {
+				f.NativeType = overrides.protoType
+				f.EnumValues = nil
+			}
+			fields = append(fields, f)
+		}
+		t.Fields = fields
+	}
+
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			if name, ok := renamedTypes[f.NativeType]; ok {
+				f.NativeType = name
+			}
+		}
+	}
+
+	methods := make([]*surface_v1.Method, 0)
+	for _, m := range model.Methods {
+		if name, ok := renamedTypes[m.ParametersTypeName]; ok {
+			m.ParametersTypeName = name
+		}
+		if name, ok := renamedTypes[m.ResponsesTypeName]; ok {
+			m.ResponsesTypeName = name
+		}
+		overrides := extensions.operation(m)
+		if overrides != nil && overrides.skip {
+			removeTypesOfSkippedMethod(model, m)
+			continue
+		}
+		if overrides != nil && overrides.name != "" {
+			m.HandlerName = overrides.name
+		}
+		methods = append(methods, m)
+	}
+	model.Methods = methods
+}
+
+// removeTypesOfSkippedMethod removes the request and response messages that have been generated for the
+// operation of 'method' alone.
+func removeTypesOfSkippedMethod(model *surface_v1.Model, method *surface_v1.Method) {
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		isRequest := t.TypeName == method.ParametersTypeName
+		isResponse := t.TypeName == method.ResponsesTypeName && method.Operation != "" &&
+			strings.HasPrefix(t.Name, method.Operation)
+		if !isRequest && !isResponse {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+}
+
+// fieldNumbers returns the field numbers for 'fields' of 'surfaceType'. Fields with a x-proto-field-number extension
+// keep their number, all other fields are numbered consecutively with the numbers that are still available. Numbers
+// that are reserved by protobuf (19000-19999) or larger than 536870911 are rejected.
+func fieldNumbers(surfaceType *surface_v1.Type, fields []*surface_v1.Field, extensions *protoExtensions) ([]int32, error) {
+	numbers := make([]int32, len(fields))
+	taken := make(map[int32]bool)
+	for i, f := range fields {
+		overrides := extensions.property(surfaceType.Name, f.Name)
+		if overrides == nil || overrides.fieldNumber <= 0 {
+			continue
+		}
+		if overrides.fieldNumber > int(protowire.MaxValidNumber) {
+			return nil, fmt.Errorf("field number %d of field %s in message %s is larger than %d",
+				overrides.fieldNumber, f.FieldName, surfaceType.TypeName, protowire.MaxValidNumber)
+		}
+		number := int32(overrides.fieldNumber)
+		if reserved := protowire.Number(number); reserved >= protowire.FirstReservedNumber &&
+			reserved <= protowire.LastReservedNumber {
+			return nil, fmt.Errorf("field number %d of field %s in message %s is reserved by protobuf (%d-%d)",
+				number, f.FieldName, surfaceType.TypeName, protowire.FirstReservedNumber, protowire.LastReservedNumber)
+		}
+		if taken[number] {
+			return nil, fmt.Errorf("field number %d is used more than once in message %s", number, surfaceType.TypeName)
+		}
+		numbers[i] = number
+		taken[number] = true
+	}
+	next := int32(1)
+	for i := range fields {
+		if numbers[i] != 0 {
+			continue
+		}
+		for taken[next] {
+			next++
+		}
+		numbers[i] = next
+		taken[next] = true
+	}
+	return numbers, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"gopkg.in/yaml.v3"
+)
+
+// FileOptions configures the package and the language-specific options of the generated .proto file. Options that
+// are empty or nil are not set, except for go_package, which defaults to ".;<package base name>".
+type FileOptions struct {
+	// The proto package, e.g.: "acme.bookstore.v1".
+	Package string `yaml:"package"`
+	// The Go import path and package name, e.g.: "github.com/acme/bookstore/v1;bookstorepb".
+	GoPackage         string `yaml:"go_package"`
+	JavaPackage       string `yaml:"java_package"`
+	JavaMultipleFiles *bool  `yaml:"java_multiple_files"`
+	CsharpNamespace   string `yaml:"csharp_namespace"`
+	PhpNamespace      string `yaml:"php_namespace"`
+	ObjcClassPrefix   string `yaml:"objc_class_prefix"`
+	RubyPackage       string `yaml:"ruby_package"`
+}
+
+// fileOptionParameters are the plugin parameters that set a single file option, e.g.: "java_package=com.acme.bookstore".
+var fileOptionParameters = []string{"package", "go_package", "java_package", "java_multiple_files", "csharp_namespace",
+	"php_namespace", "objc_class_prefix", "ruby_package"}
+
+// versionPattern matches the version of an API, e.g.: "v1", "v2beta1" or "v1alpha".
+var versionPattern = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?$`)
+
+// LoadFileOptions reads the file options from the YAML file 'fileName', e.g.:
+//
+//	package: acme.bookstore.v1
+//	go_package: github.com/acme/bookstore/v1;bookstorepb
+//	java_package: com.acme.bookstore.v1
+//	java_multiple_files: true
+func LoadFileOptions(fileName string) (*FileOptions, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	options := &FileOptions{}
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(options); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("invalid file options %s: %v", fileName, err)
+	}
+	return options, nil
+}
+
+// set sets the option of the plugin parameter 'name' to 'value'.
+func (o *FileOptions) set(name string, value string) error {
+	switch name {
+	case "package":
+		o.Package = value
+	case "go_package":
+		o.GoPackage = value
+	case "java_package":
+		o.JavaPackage = value
+	case "java_multiple_files":
+		multipleFiles, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("unsupported value for parameter java_multiple_files: %s", value)
+		}
+		o.JavaMultipleFiles = &multipleFiles
+	case "csharp_namespace":
+		o.CsharpNamespace = value
+	case "php_namespace":
+		o.PhpNamespace = value
+	case "objc_class_prefix":
+		o.ObjcClassPrefix = value
+	case "ruby_package":
+		o.RubyPackage = value
+	default:
+		return fmt.Errorf("unsupported parameter name: %s", name)
+	}
+	return nil
+}
+
+// merge sets the options that are set in 'other'.
+func (o *FileOptions) merge(other *FileOptions) {
+	for _, option := range []struct {
+		value *string
+		other string
+	}{
+		{&o.Package, other.Package},
+		{&o.GoPackage, other.GoPackage},
+		{&o.JavaPackage, other.JavaPackage},
+		{&o.CsharpNamespace, other.CsharpNamespace},
+		{&o.PhpNamespace, other.PhpNamespace},
+		{&o.ObjcClassPrefix, other.ObjcClassPrefix},
+		{&o.RubyPackage, other.RubyPackage},
+	} {
+		if option.other != "" {
+			*option.value = option.other
+		}
+	}
+	if other.JavaMultipleFiles != nil {
+		o.JavaMultipleFiles = other.JavaMultipleFiles
+	}
+}
+
+// buildFileOptions returns the options of the generated .proto file.
+func (renderer *Renderer) buildFileOptions() *dpb.FileOptions {
+	goPackage := ".;" + packageBaseName(renderer.Package)
+	fileOptions := &dpb.FileOptions{
+		GoPackage: &goPackage,
+	}
+	options := renderer.FileOptions
+	if options == nil {
+		return fileOptions
+	}
+	if options.GoPackage != "" {
+		fileOptions.GoPackage = &options.GoPackage
+	}
+	if options.JavaMultipleFiles != nil {
+		multipleFiles := *options.JavaMultipleFiles
+		fileOptions.JavaMultipleFiles = &multipleFiles
+	}
+	for _, option := range []struct {
+		target **string
+		value  string
+	}{
+		{&fileOptions.JavaPackage, options.JavaPackage},
+		{&fileOptions.CsharpNamespace, options.CsharpNamespace},
+		{&fileOptions.PhpNamespace, options.PhpNamespace},
+		{&fileOptions.ObjcClassPrefix, options.ObjcClassPrefix},
+		{&fileOptions.RubyPackage, options.RubyPackage},
+	} {
+		if option.value != "" {
+			value := option.value
+			*option.target = &value
+		}
+	}
+	return fileOptions
+}
+
+// protoFilePath returns the path of the .proto file of the package 'packageName', which follows the directory layout
+// of the package, e.g.: "acme/bookstore/v1/bookstore.proto" for "acme.bookstore.v1" and "bookstore.proto" for
+// "bookstore".
+func protoFilePath(packageName string) string {
+	parts := strings.Split(packageName, ".")
+	if len(parts) == 1 {
+		return packageName + ".proto"
+	}
+	return strings.Join(parts, "/") + "/" + packageBaseName(packageName) + ".proto"
+}
+
+// packageBaseName returns the last part of a dot-separated package name that isn't a version, e.g.: "bookstore" for
+// "acme.bookstore" and "acme.bookstore.v1".
+func packageBaseName(packageName string) string {
+	parts := strings.Split(packageName, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		if !versionPattern.MatchString(parts[i]) {
+			return parts[i]
+		}
+	}
+	return parts[0]
+}
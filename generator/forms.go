@@ -0,0 +1,105 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	"github.com/google/gnostic-grpc/utils"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+// buildFormComment documents the layout of the form request body of 'operation', which is passed through as
+// google.api.HttpBody, e.g.:
+//
+//	The request body is encoded as multipart/form-data and has the fields:
+//	  - title: string (required)
+//	  - cover: string/binary, encoded as image/png
+//
+// An empty string is returned if the request body of 'operation' isn't a form.
+func buildFormComment(document *openapiv3.Document, operation *openapiv3.Operation) string {
+	requestBody := resolveRequestBody(document, operation.GetRequestBody())
+	mediaTypes := make([]string, 0)
+	for _, namedMediaType := range requestBody.GetContent().GetAdditionalProperties() {
+		mediaTypes = append(mediaTypes, namedMediaType.Name)
+	}
+	if !hasOnlyHttpBodyMediaTypes(mediaTypes) {
+		return ""
+	}
+
+	paragraphs := make([]string, 0)
+	for _, namedMediaType := range requestBody.GetContent().GetAdditionalProperties() {
+		if !utils.IsFormMediaType(namedMediaType.Name) {
+			continue
+		}
+		lines := []string{"The request body is encoded as " + namedMediaType.Name + " and has the fields:"}
+		encodings := make(map[string]string)
+		for _, namedEncoding := range namedMediaType.GetValue().GetEncoding().GetAdditionalProperties() {
+			encodings[namedEncoding.Name] = namedEncoding.GetValue().GetContentType()
+		}
+		schema := utils.ResolveSchema(document, namedMediaType.GetValue().GetSchema())
+		required := make(map[string]bool)
+		for _, name := range schema.GetRequired() {
+			required[name] = true
+		}
+		for _, property := range schema.GetProperties().GetAdditionalProperties() {
+			line := "  - " + property.Name + ": " + describeFormFieldType(property.Value)
+			if required[property.Name] {
+				line += " (required)"
+			}
+			if encodings[property.Name] != "" {
+				line += ", encoded as " + encodings[property.Name]
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 1 {
+			lines[0] = "The request body is encoded as " + namedMediaType.Name + "."
+		}
+		paragraphs = append(paragraphs, strings.Join(lines, "\n"))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// describeFormFieldType describes the type of a form field, e.g.: "string/binary", "array of integer" or "Address".
+func describeFormFieldType(schemaOrReference *openapiv3.SchemaOrReference) string {
+	if reference := schemaOrReference.GetReference(); reference != nil {
+		return reference.XRef[strings.LastIndex(reference.XRef, "/")+1:]
+	}
+	schema := schemaOrReference.GetSchema()
+	switch {
+	case schema.GetType() == "array" && len(schema.GetItems().GetSchemaOrReference()) > 0:
+		return "array of " + describeFormFieldType(schema.GetItems().GetSchemaOrReference()[0])
+	case schema.GetType() == "":
+		return "object"
+	case schema.GetFormat() != "":
+		return schema.GetType() + "/" + schema.GetFormat()
+	}
+	return schema.GetType()
+}
+
+// resolveRequestBody returns the request body of 'requestBody', which may reference a request body of the components
+// of 'document'. nil is returned if the reference can't be resolved.
+func resolveRequestBody(document *openapiv3.Document, requestBody *openapiv3.RequestBodyOrReference) *openapiv3.RequestBody {
+	reference := requestBody.GetReference()
+	if reference == nil {
+		return requestBody.GetRequestBody()
+	}
+	for _, named := range document.GetComponents().GetRequestBodies().GetAdditionalProperties() {
+		if reference.XRef == "#/components/requestBodies/"+named.Name {
+			return named.GetValue().GetRequestBody()
+		}
+	}
+	return nil
+}
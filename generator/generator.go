@@ -22,10 +22,13 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/descriptor"
+	"github.com/golang/protobuf/proto"
 	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/golang/protobuf/ptypes/empty"
+	plugins "github.com/google/gnostic/plugins"
 	surface_v1 "github.com/google/gnostic/surface"
 	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/google/gnostic-grpc/utils"
 )
@@ -41,10 +44,11 @@ var generatedSymbolicReferences = make(map[string]bool, 0)
 //     current description.
 //  2. buildDependencies to build all static FileDescriptorProto we need.
 //  3. buildAllMessageDescriptors is called to create all messages which will be rendered in .proto
-//  4. buildAllServiceDescriptors is called to create an RPC service which will be rendered in .proto
+//  4. buildAllServiceDescriptors is called to create an RPC service which will be rendered in .proto. Operations that
+//     share one RPC have been merged by groupAdditionalBindings before.
 func (renderer *Renderer) runFileDescriptorSetGenerator() (fdSet *dpb.FileDescriptorSet, err error) {
 	syntax := "proto3"
-	n := renderer.Package + ".proto"
+	n := protoFilePath(renderer.Package)
 
 	protoToBeRendered := &dpb.FileDescriptorProto{
 		Name:    &n,
@@ -56,9 +60,44 @@ func (renderer *Renderer) runFileDescriptorSetGenerator() (fdSet *dpb.FileDescri
 	if err != nil {
 		return nil, err
 	}
+	addUpdateMasks(renderer)
+	inlineRequestBodies(renderer)
+	renderer.unboundPathVariables = make([]*plugins.Message, 0)
+	if err := groupAdditionalBindings(renderer); err != nil {
+		return nil, err
+	}
+	renderer.operationInfos = applyLongRunningOperations(renderer)
+	if renderer.InferResources {
+		renderer.resources = inferResources(renderer)
+	}
+	if renderer.DetectPagination {
+		renderer.pagination = detectPagination(renderer, renderer.PaginationFields)
+	}
 	dependencies := buildDependencies()
 	dependencies = append(dependencies, symbolicReferenceDependencies...)
 	dependencyNames := getNamesOfDependenciesThatWillBeImported(dependencies, renderer.Model.Methods)
+
+	typeMappingDependencies, typeMappingImports, err := buildTypeMappingDependencies(renderer.Model, renderer.TypeMappings)
+	if err != nil {
+		return nil, err
+	}
+	dependencyNames = append(dependencyNames, typeMappingImports...)
+	wellKnownDependencies, wellKnownImports, err := buildWellKnownDependencies(renderer.Model, dependencyNames)
+	if err != nil {
+		return nil, err
+	}
+	dependencyNames = append(dependencyNames, wellKnownImports...)
+	metadataDependencies, metadataImports, err := buildMetadataDependencies(renderer, dependencyNames)
+	if err != nil {
+		return nil, err
+	}
+	dependencyNames = append(dependencyNames, metadataImports...)
+	resourceDependencies, resourceImports, err := buildResourceDependencies(renderer, dependencyNames)
+	if err != nil {
+		return nil, err
+	}
+	dependencyNames = append(dependencyNames, resourceImports...)
+	sort.Strings(dependencyNames)
 	protoToBeRendered.Dependency = dependencyNames
 
 	allMessages, err := buildAllMessageDescriptors(renderer)
@@ -67,22 +106,28 @@ func (renderer *Renderer) runFileDescriptorSetGenerator() (fdSet *dpb.FileDescri
 	}
 	protoToBeRendered.MessageType = allMessages
 
-	allServices, err := buildAllServiceDescriptors(protoToBeRendered.MessageType, renderer)
+	allServices, serviceDescriptions, methodComments, err := buildAllServiceDescriptors(protoToBeRendered.MessageType, renderer)
 	if err != nil {
 		return nil, err
 	}
 	protoToBeRendered.Service = allServices
 
-	sourceCodeInfo, err := buildSourceCodeInfo(renderer.Model.Types)
+	sourceCodeInfo, err := buildSourceCodeInfo(renderer.Model.Types, allServices, serviceDescriptions, methodComments)
 	if err != nil {
 		return nil, err
 	}
 	protoToBeRendered.SourceCodeInfo = sourceCodeInfo
-
-	fileOptions := renderer.buildFileOptions()
-	protoToBeRendered.Options = fileOptions
+	protoToBeRendered.Options = renderer.buildFileOptions()
+	orderFileElements(protoToBeRendered)
+	if err := validateFileDescriptor(renderer, protoToBeRendered); err != nil {
+		return nil, err
+	}
 
 	allFileDescriptors := append(symbolicReferenceDependencies, dependencies...)
+	allFileDescriptors = appendMissingFiles(allFileDescriptors, typeMappingDependencies...)
+	allFileDescriptors = appendMissingFiles(allFileDescriptors, wellKnownDependencies...)
+	allFileDescriptors = appendMissingFiles(allFileDescriptors, metadataDependencies...)
+	allFileDescriptors = appendMissingFiles(allFileDescriptors, resourceDependencies...)
 	allFileDescriptors = append(allFileDescriptors, protoToBeRendered)
 	fdSet = &dpb.FileDescriptorSet{
 		File: allFileDescriptors,
@@ -92,8 +137,8 @@ func (renderer *Renderer) runFileDescriptorSetGenerator() (fdSet *dpb.FileDescri
 }
 
 // buildSourceCodeInfo builds the object which holds additional information, such as the description from OpenAPI
-// components. This information will be rendered as a comment in the final .proto file.
-func buildSourceCodeInfo(types []*surface_v1.Type) (sourceCodeInfo *dpb.SourceCodeInfo, err error) {
+// components or tags. This information will be rendered as a comment in the final .proto file.
+func buildSourceCodeInfo(types []*surface_v1.Type, services []*dpb.ServiceDescriptorProto, serviceDescriptions []string, methodComments [][]string) (sourceCodeInfo *dpb.SourceCodeInfo, err error) {
 	allLocations := make([]*dpb.SourceCodeInfo_Location, 0)
 	for idx, surfaceType := range types {
 		location := &dpb.SourceCodeInfo_Location{
@@ -102,12 +147,82 @@ func buildSourceCodeInfo(types []*surface_v1.Type) (sourceCodeInfo *dpb.SourceCo
 		}
 		allLocations = append(allLocations, location)
 	}
+	for idx := range serviceDescriptions {
+		location := &dpb.SourceCodeInfo_Location{
+			Path:            []int32{6, int32(idx)},
+			LeadingComments: &serviceDescriptions[idx],
+		}
+		allLocations = append(allLocations, location)
+		for methodIdx := range methodComments[idx] {
+			location := &dpb.SourceCodeInfo_Location{
+				Path:            []int32{6, int32(idx), 2, int32(methodIdx)},
+				LeadingComments: &methodComments[idx][methodIdx],
+			}
+			allLocations = append(allLocations, location)
+			// Options are element 4 of a method.
+			path := []int32{6, int32(idx), 2, int32(methodIdx), 4}
+			allLocations = append(allLocations, buildOptionLocations(path, services[idx].Method[methodIdx].Options)...)
+		}
+	}
 	sourceCodeInfo = &dpb.SourceCodeInfo{
 		Location: allLocations,
 	}
 	return sourceCodeInfo, nil
 }
 
+// buildOptionLocations returns one location per option of the options at 'path', ordered by field number. Without them
+// the printer would output several options of an element in random order.
+func buildOptionLocations(path []int32, options proto.Message) []*dpb.SourceCodeInfo_Location {
+	if !proto.MessageReflect(options).IsValid() {
+		return nil
+	}
+	numbers := make([]int, 0)
+	proto.MessageReflect(options).Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		numbers = append(numbers, int(field.Number()))
+		return true
+	})
+	sort.Ints(numbers)
+	locations := make([]*dpb.SourceCodeInfo_Location, 0)
+	for idx, number := range numbers {
+		locations = append(locations, &dpb.SourceCodeInfo_Location{
+			Path: append(append([]int32{}, path...), int32(number)),
+			Span: []int32{int32(idx), 0, 0},
+		})
+	}
+	return locations
+}
+
+// orderFileElements sets the spans of the top-level elements of 'file', so that the printer outputs the file options
+// ordered by field number, followed by the messages, enums and services in declaration order. Elements without a span
+// are printed first, hence every element needs one as soon as one option has one.
+func orderFileElements(file *dpb.FileDescriptorProto) {
+	// Options are element 8 of a file.
+	optionLocations := buildOptionLocations([]int32{8}, file.Options)
+	file.SourceCodeInfo.Location = append(file.SourceCodeInfo.Location, optionLocations...)
+	locations := make(map[[2]int32]*dpb.SourceCodeInfo_Location)
+	for _, location := range file.SourceCodeInfo.Location {
+		if len(location.Path) == 2 {
+			locations[[2]int32{location.Path[0], location.Path[1]}] = location
+		}
+	}
+	line := int32(len(optionLocations))
+	// Messages, enums and services are the elements 4, 5 and 6 of a file.
+	for _, element := range []struct {
+		tag   int32
+		count int
+	}{{4, len(file.MessageType)}, {5, len(file.EnumType)}, {6, len(file.Service)}} {
+		for idx := 0; idx < element.count; idx++ {
+			location := locations[[2]int32{element.tag, int32(idx)}]
+			if location == nil {
+				location = &dpb.SourceCodeInfo_Location{Path: []int32{element.tag, int32(idx)}}
+				file.SourceCodeInfo.Location = append(file.SourceCodeInfo.Location, location)
+			}
+			location.Span = []int32{line, 0, 0}
+			line++
+		}
+	}
+}
+
 // buildSymbolicReferences recursively generates all .proto definitions to external OpenAPI descriptions (URLs to other
 // descriptions inside the current description).
 func buildSymbolicReferences(renderer *Renderer) (symbolicFileDescriptors []*dpb.FileDescriptorProto, err error) {
@@ -115,6 +230,10 @@ func buildSymbolicReferences(renderer *Renderer) (symbolicFileDescriptors []*dpb
 	symbolicReferences = trimAndRemoveDuplicates(symbolicReferences)
 
 	for _, ref := range symbolicReferences {
+		if renderer.TypeMappings.coversSymbolicReference(renderer.Document, ref) {
+			// All types of that description are mapped onto existing proto types.
+			continue
+		}
 		if _, alreadyGenerated := generatedSymbolicReferences[ref]; !alreadyGenerated {
 			generatedSymbolicReferences[ref] = true
 
@@ -142,10 +261,27 @@ func buildSymbolicReferences(renderer *Renderer) (symbolicFileDescriptors []*dpb
 			if document.Openapi == "2.0.0" {
 				inputDocumentType = "openapi.v2.Document"
 			}
-			NewProtoLanguageModel().Prepare(surfaceModel, inputDocumentType)
+			language := NewProtoLanguageModel()
+			language.NamingStrategy = renderer.NamingStrategy
+			language.Document = document
+			language.TypeMappings = renderer.TypeMappings
+			language.OneofResponses = renderer.OneofResponses
+			language.MetadataParameters = renderer.MetadataParameters
+			language.Prepare(surfaceModel, inputDocumentType)
 
 			// Recursively call the generator.
 			recursiveRenderer := NewRenderer(surfaceModel)
+			recursiveRenderer.NamingStrategy = renderer.NamingStrategy
+			recursiveRenderer.Document = document
+			recursiveRenderer.TypeMappings = renderer.TypeMappings
+			recursiveRenderer.ServicesPerTag = renderer.ServicesPerTag
+			recursiveRenderer.WholeMessageBodies = renderer.WholeMessageBodies
+			recursiveRenderer.ErrorComments = renderer.ErrorComments
+			recursiveRenderer.errorResponses = language.errorResponses
+			recursiveRenderer.oneofTypes = language.oneofTypes
+			recursiveRenderer.OneofResponses = renderer.OneofResponses
+			recursiveRenderer.MetadataParameters = renderer.MetadataParameters
+			recursiveRenderer.metadataParameters = language.metadataParameters
 			fileName := path.Base(ref)
 			recursiveRenderer.Package = strings.TrimSuffix(fileName, filepath.Ext(fileName))
 			newFdSet, err := recursiveRenderer.runFileDescriptorSetGenerator()
@@ -205,6 +341,39 @@ func buildDependencies() (dependencies []*dpb.FileDescriptorProto) {
 	return dependencies
 }
 
+// wellKnownTypes maps the types the generator uses on its own onto the files that define them.
+var wellKnownTypes = map[string]string{
+	httpBodyTypeName:  "google/api/httpbody.proto",
+	fieldMaskTypeName: "google/protobuf/field_mask.proto",
+	operationTypeName: "google/longrunning/operations.proto",
+}
+
+// buildWellKnownDependencies returns the FileDescriptorProtos of the well-known types that are used inside 'model'
+// (including their transitive dependencies) and the names of the files that have to be imported. Files that are
+// already contained in 'imports' are skipped.
+func buildWellKnownDependencies(model *surface_v1.Model, imports []string) (dependencies []*dpb.FileDescriptorProto, newImports []string, err error) {
+	usedTypes := usedTypeNames(model)
+	typeNames := make([]string, 0)
+	for typeName := range wellKnownTypes {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	for _, typeName := range typeNames {
+		importPath := wellKnownTypes[typeName]
+		if !usedTypes[typeName] || utils.Contains(imports, importPath) || utils.Contains(newImports, importPath) {
+			continue
+		}
+		files, err := loadFileDescriptorProtos(importPath, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		dependencies = appendMissingFiles(dependencies, files...)
+		newImports = append(newImports, importPath)
+	}
+	return dependencies, newImports, nil
+}
+
 // getNamesOfDependenciesThatWillBeImported adds the dependencies to the FileDescriptorProto we want to render (the last one). This essentially
 // makes the 'import'  statements inside the .proto definition.
 func getNamesOfDependenciesThatWillBeImported(dependencies []*dpb.FileDescriptorProto, methods []*surface_v1.Method) (names []string) {
@@ -253,11 +422,3 @@ func trimAndRemoveDuplicates(urls []string) []string {
 func getLast(protos []*dpb.FileDescriptorProto) *dpb.FileDescriptorProto {
 	return protos[len(protos)-1]
 }
-
-func (renderer *Renderer) buildFileOptions() *dpb.FileOptions {
-	goPackage := ".;" + renderer.Package
-	fileOptions := &dpb.FileOptions{
-		GoPackage: &goPackage,
-	}
-	return fileOptions
-}
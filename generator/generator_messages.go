@@ -5,6 +5,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
 	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	surface_v1 "github.com/google/gnostic/surface"
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -18,11 +19,21 @@ var generatedMessages = make(map[string]string, 0)
 // buildAllMessageDescriptors builds protobuf messages from the surface model types. If the type is a RPC request parameter
 // the fields have to follow certain rules, and therefore have to be validated.
 func buildAllMessageDescriptors(renderer *Renderer) (messageDescriptors []*dpb.DescriptorProto, err error) {
+	extensions := newProtoExtensions(renderer.Document)
+	// Register the messages first, so that fields don't reference messages of the same name from other files.
+	for _, surfaceType := range renderer.Model.Types {
+		generatedMessages[surfaceType.TypeName] = renderer.Package + "." + surfaceType.TypeName
+	}
 	for _, surfaceType := range renderer.Model.Types {
 		message := &dpb.DescriptorProto{}
 		message.Name = &surfaceType.TypeName
 
-		for i, surfaceField := range surfaceTypeFields(surfaceType) {
+		fields := surfaceTypeFields(surfaceType)
+		numbers, err := fieldNumbers(surfaceType, fields, extensions)
+		if err != nil {
+			return nil, err
+		}
+		for i, surfaceField := range fields {
 			if strings.Contains(surfaceField.NativeType, "map[string][]") {
 				// Not supported for now: https://github.com/LorenzHW/gnostic-grpc-deprecated/issues/3#issuecomment-509348357
 				continue
@@ -31,11 +42,23 @@ func buildAllMessageDescriptors(renderer *Renderer) (messageDescriptors []*dpb.D
 				validateRequestParameter(surfaceField)
 			}
 
-			addFieldDescriptor(message, surfaceField, i, renderer.Package)
+			addFieldDescriptor(message, surfaceField, numbers[i], renderer.Package)
 			addEnumDescriptorIfNecessary(message, surfaceField)
+			renderer.pagination.setJsonName(message, message.Field[len(message.Field)-1])
+			if err := renderer.resources.setFieldOptions(message, message.Field[len(message.Field)-1]); err != nil {
+				return nil, err
+			}
+		}
+		if err := renderer.resources.setMessageOptions(message); err != nil {
+			return nil, err
+		}
+		if oneofName, ok := renderer.oneofTypes[surfaceType]; ok {
+			message.OneofDecl = []*dpb.OneofDescriptorProto{{Name: &oneofName}}
+			for _, field := range message.Field {
+				field.OneofIndex = proto.Int32(0)
+			}
 		}
 		messageDescriptors = append(messageDescriptors, message)
-		generatedMessages[*message.Name] = renderer.Package + "." + *message.Name
 	}
 	return messageDescriptors, nil
 }
@@ -124,9 +147,8 @@ func validateQueryParameter(field *surface_v1.Field) {
 
 }
 
-func addFieldDescriptor(message *dpb.DescriptorProto, surfaceField *surface_v1.Field, idx int, packageName string) {
-	count := int32(idx + 1)
-	fieldDescriptor := &dpb.FieldDescriptorProto{Number: &count, Name: &surfaceField.FieldName}
+func addFieldDescriptor(message *dpb.DescriptorProto, surfaceField *surface_v1.Field, number int32, packageName string) {
+	fieldDescriptor := &dpb.FieldDescriptorProto{Number: &number, Name: &surfaceField.FieldName}
 	fieldDescriptor.Type = getFieldDescriptorType(surfaceField.NativeType, surfaceField.EnumValues)
 	fieldDescriptor.Label = getFieldDescriptorLabel(surfaceField)
 	fieldDescriptor.TypeName = getFieldDescriptorTypeName(*fieldDescriptor.Type, surfaceField, packageName)
@@ -154,6 +176,11 @@ func getFieldDescriptorType(nativeType string, enumValues []string) *dpb.FieldDe
 // descriptor or enum. Otherwise, it is nil. Names are set according to the protocol buffer style guide for message names:
 // https://developers.google.com/protocol-buffers/docs/style#message-and-field-names
 func getFieldDescriptorTypeName(fieldDescriptorType descriptorpb.FieldDescriptorProto_Type, field *surface_v1.Field, packageName string) *string {
+	if fieldDescriptorType == dpb.FieldDescriptorProto_TYPE_MESSAGE && isFullyQualifiedTypeName(field.NativeType) {
+		// A reference to an existing proto type (e.g. from a type mapping).
+		t := "." + strings.TrimPrefix(field.NativeType, ".")
+		return &t
+	}
 	if fieldHasAReferenceToAMessageInAnotherDependency(field, fieldDescriptorType) {
 		t := generatedMessages[field.NativeType]
 		return &t
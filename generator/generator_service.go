@@ -6,29 +6,141 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/transcoding"
+	openapiv3 "github.com/google/gnostic/openapiv3"
 	surface_v1 "github.com/google/gnostic/surface"
 	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/genproto/googleapis/longrunning"
 )
 
-// buildAllServiceDescriptors builds a protobuf RPC service. For every method the corresponding gRPC-HTTP transcoding options (https://github.com/googleapis/googleapis/blob/master/google/api/http.proto)
-// have to be set.
-func buildAllServiceDescriptors(messages []*dpb.DescriptorProto, renderer *Renderer) (services []*dpb.ServiceDescriptorProto, err error) {
-	serviceName := findValidServiceName(messages, strings.Title(renderer.Package))
-	methodDescriptors, err := buildAllMethodDescriptors(renderer.Model.Methods, renderer.Model.Types)
-	if err != nil {
-		return nil, err
+// The specification extension of an operation that sets the service of the operation if services are split by tags.
+const extensionGrpcService = "x-grpc-service"
+
+// buildAllServiceDescriptors builds the protobuf RPC services. For every method the corresponding gRPC-HTTP transcoding options (https://github.com/googleapis/googleapis/blob/master/google/api/http.proto)
+// have to be set. By default, all methods are inside one service named after the package. If ServicesPerTag is set
+// there is one service per tag. The descriptions of the services and the comments of their methods are returned as well.
+func buildAllServiceDescriptors(messages []*dpb.DescriptorProto, renderer *Renderer) (services []*dpb.ServiceDescriptorProto, descriptions []string, methodComments [][]string, err error) {
+	defaultServiceName := renderer.NamingStrategy.ServiceName(packageBaseName(renderer.Package))
+	takenNames := make([]*dpb.DescriptorProto, len(messages))
+	copy(takenNames, messages)
+
+	renderer.rpcMethods = make(map[string]*surface_v1.Method)
+	for _, group := range groupMethodsByService(renderer) {
+		serviceName := defaultServiceName
+		if group.tag != "" {
+			serviceName = renderer.NamingStrategy.ServiceName(group.tag)
+		}
+		serviceName = findValidServiceName(takenNames, serviceName)
+		takenNames = append(takenNames, &dpb.DescriptorProto{Name: &serviceName})
+
+		methodDescriptors, err := buildAllMethodDescriptors(group.methods, renderer)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for i, m := range group.methods {
+			renderer.rpcMethods[renderer.Package+"."+serviceName+"."+methodDescriptors[i].GetName()] = m
+		}
+		service := &dpb.ServiceDescriptorProto{
+			Name:   &serviceName,
+			Method: methodDescriptors,
+		}
+		services = append(services, service)
+		descriptions = append(descriptions, group.description)
+		comments := make([]string, 0)
+		for _, m := range group.methods {
+			comments = append(comments, buildMethodComment(m, renderer))
+		}
+		methodComments = append(methodComments, comments)
+	}
+	return services, descriptions, methodComments, nil
+}
+
+// buildMethodComment builds the comment of the RPC of 'method'. It documents what can't be expressed in .proto, e.g.
+// the mapping of the query string, the fields of a form request body, the metadata or the error responses (if
+// ErrorComments is set).
+func buildMethodComment(method *surface_v1.Method, renderer *Renderer) string {
+	paragraphs := make([]string, 0)
+	if operation := findOperation(renderer.Document, method.Method, method.Path); operation != nil {
+		paragraphs = append(paragraphs, buildQueryComment(renderer.Document, operation))
+		paragraphs = append(paragraphs, buildFormComment(renderer.Document, operation))
+	}
+	paragraphs = append(paragraphs, buildMetadataComment(renderer.metadataParameters[method]))
+	if renderer.ErrorComments {
+		paragraphs = append(paragraphs, buildErrorComment(renderer.errorResponses[method]))
+	}
+
+	comment := make([]string, 0)
+	for _, paragraph := range paragraphs {
+		if paragraph != "" {
+			comment = append(comment, paragraph)
+		}
 	}
-	service := &dpb.ServiceDescriptorProto{
-		Name:   &serviceName,
-		Method: methodDescriptors,
+	return strings.Join(comment, "\n\n")
+}
+
+// serviceGroup holds the methods of a single service.
+type serviceGroup struct {
+	tag         string // empty for the default service
+	description string
+	methods     []*surface_v1.Method
+}
+
+// groupMethodsByService groups the methods of the model by the service they belong to. The groups are ordered by
+// the first occurrence of a method, the default service (holding all untagged methods) comes first.
+func groupMethodsByService(renderer *Renderer) []*serviceGroup {
+	defaultGroup := &serviceGroup{}
+	if !renderer.ServicesPerTag {
+		defaultGroup.methods = renderer.Model.Methods
+		return []*serviceGroup{defaultGroup}
 	}
-	services = append(services, service)
-	return services, nil
+
+	tagDescriptions := make(map[string]string)
+	for _, tag := range renderer.Document.GetTags() {
+		tagDescriptions[tag.Name] = tag.Description
+	}
+
+	groups := []*serviceGroup{defaultGroup}
+	groupsByTag := make(map[string]*serviceGroup)
+	for _, m := range renderer.Model.Methods {
+		tag := findServiceTag(renderer.Document, m)
+		if tag == "" {
+			defaultGroup.methods = append(defaultGroup.methods, m)
+			continue
+		}
+		group, ok := groupsByTag[tag]
+		if !ok {
+			group = &serviceGroup{tag: tag, description: tagDescriptions[tag]}
+			groupsByTag[tag] = group
+			groups = append(groups, group)
+		}
+		group.methods = append(group.methods, m)
+	}
+
+	if len(defaultGroup.methods) == 0 {
+		groups = groups[1:]
+	}
+	return groups
 }
 
-func buildAllMethodDescriptors(methods []*surface_v1.Method, types []*surface_v1.Type) (allMethodDescriptors []*dpb.MethodDescriptorProto, err error) {
+// findServiceTag returns the tag that determines the service of 'method': either the value of the x-grpc-service
+// extension or the first tag of the operation.
+func findServiceTag(document *openapiv3.Document, method *surface_v1.Method) string {
+	operation := findOperation(document, method.Method, method.Path)
+	if operation == nil {
+		return ""
+	}
+	if service, ok := stringExtension(operation.SpecificationExtension, extensionGrpcService); ok {
+		return service
+	}
+	if len(operation.Tags) > 0 {
+		return operation.Tags[0]
+	}
+	return ""
+}
+
+func buildAllMethodDescriptors(methods []*surface_v1.Method, renderer *Renderer) (allMethodDescriptors []*dpb.MethodDescriptorProto, err error) {
 	for _, method := range methods {
-		methodDescriptor, err := buildMethodDescriptor(method, types)
+		methodDescriptor, err := buildMethodDescriptor(method, renderer)
 		if err != nil {
 			return nil, err
 		}
@@ -37,8 +149,13 @@ func buildAllMethodDescriptors(methods []*surface_v1.Method, types []*surface_v1
 	return allMethodDescriptors, nil
 }
 
-func buildMethodDescriptor(method *surface_v1.Method, types []*surface_v1.Type) (methodDescriptor *dpb.MethodDescriptorProto, err error) {
-	options, err := buildMethodOptions(method, types)
+func buildMethodDescriptor(method *surface_v1.Method, renderer *Renderer) (methodDescriptor *dpb.MethodDescriptorProto, err error) {
+	options, err := buildMethodOptions(method, renderer)
+	if err != nil {
+		return nil, err
+	}
+	clientStreaming, serverStreaming, err := getStreamingForOperation(
+		findOperation(renderer.Document, method.Method, method.Path))
 	if err != nil {
 		return nil, err
 	}
@@ -49,56 +166,111 @@ func buildMethodDescriptor(method *surface_v1.Method, types []*surface_v1.Type)
 		OutputType: &outputType,
 		Options:    options,
 	}
+	if clientStreaming {
+		methodDescriptor.ClientStreaming = &clientStreaming
+	}
+	if serverStreaming {
+		methodDescriptor.ServerStreaming = &serverStreaming
+	}
 	return methodDescriptor, nil
 }
 
-func buildMethodOptions(method *surface_v1.Method, types []*surface_v1.Type) (options *dpb.MethodOptions, err error) {
+func buildMethodOptions(method *surface_v1.Method, renderer *Renderer) (options *dpb.MethodOptions, err error) {
 	options = &dpb.MethodOptions{}
-	httpRule := getHttpRuleForMethod(method)
-	httpRule.Body = getRequestBodyForRequestParameter(method.ParametersTypeName, types)
-	if err := proto.SetExtension(options, annotations.E_Http, &httpRule); err != nil {
+	httpRule, err := renderer.getHttpRuleForMethod(method)
+	if err != nil {
 		return nil, err
 	}
+	httpRule.Body = renderer.getRequestBody(method)
+	httpRule.ResponseBody = getResponseBodyForResponse(method.ResponsesTypeName, renderer.Model.Types)
+	httpRule.AdditionalBindings = renderer.additionalBindings[method]
+	if err := proto.SetExtension(options, annotations.E_Http, httpRule); err != nil {
+		return nil, err
+	}
+	if info := renderer.operationInfos[method]; info != nil {
+		if err := proto.SetExtension(options, longrunning.E_OperationInfo, info); err != nil {
+			return nil, err
+		}
+	}
+	if err := renderer.resources.setMethodOptions(method, options); err != nil {
+		return nil, err
+	}
+	if metadata := renderer.metadataParameters[method]; len(metadata) > 0 {
+		if err := proto.SetExtension(options, transcoding.E_Metadata, metadata); err != nil {
+			return nil, err
+		}
+	}
 	return options, nil
 }
 
 // getHttpRuleForMethod constructs a HttpRule from google/api/http.proto. Enables gRPC-HTTP transcoding on 'method'.
-// If not nil, body is also set.
-func getHttpRuleForMethod(method *surface_v1.Method) annotations.HttpRule {
-	var httpRule annotations.HttpRule
+// HTTP methods without a dedicated pattern (HEAD, OPTIONS and TRACE) are mapped onto a custom pattern. The path is
+// translated into a path template whose variables reference the fields of the request message, and the server base
+// path is prefixed to or stripped from it if requested.
+func (renderer *Renderer) getHttpRuleForMethod(method *surface_v1.Method) (*annotations.HttpRule, error) {
+	path, unbound, err := translatePathTemplate(method, renderer.Model.Types)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range unbound {
+		text := "The path variable " + name + " of " + operationKey(method.Method, method.Path) + " has no field in " +
+			"the request message, the path template keeps its name."
+		msg := constructWarningMessage("PATHTEMPLATE", text, []string{"paths", method.Path, strings.ToLower(method.Method)})
+		renderer.unboundPathVariables = append(renderer.unboundPathVariables, &msg)
+	}
+	path = renderer.applyBasePath(path)
+	var httpRule *annotations.HttpRule
 	switch method.Method {
 	case "GET":
-		httpRule = annotations.HttpRule{
+		httpRule = &annotations.HttpRule{
 			Pattern: &annotations.HttpRule_Get{
-				Get: method.Path,
+				Get: path,
 			},
 		}
 	case "POST":
-		httpRule = annotations.HttpRule{
+		httpRule = &annotations.HttpRule{
 			Pattern: &annotations.HttpRule_Post{
-				Post: method.Path,
+				Post: path,
 			},
 		}
 	case "PUT":
-		httpRule = annotations.HttpRule{
+		httpRule = &annotations.HttpRule{
 			Pattern: &annotations.HttpRule_Put{
-				Put: method.Path,
+				Put: path,
 			},
 		}
 	case "PATCH":
-		httpRule = annotations.HttpRule{
+		httpRule = &annotations.HttpRule{
 			Pattern: &annotations.HttpRule_Patch{
-				Patch: method.Path,
+				Patch: path,
 			},
 		}
 	case "DELETE":
-		httpRule = annotations.HttpRule{
+		httpRule = &annotations.HttpRule{
 			Pattern: &annotations.HttpRule_Delete{
-				Delete: method.Path,
+				Delete: path,
+			},
+		}
+	default:
+		httpRule = &annotations.HttpRule{
+			Pattern: &annotations.HttpRule_Custom{
+				Custom: &annotations.CustomHttpPattern{
+					Kind: method.Method,
+					Path: path,
+				},
 			},
 		}
 	}
-	return httpRule
+	return httpRule, nil
+}
+
+// getRequestBody returns the body of the HttpRule of 'method': either "*" for whole-message bodies or the name of the
+// field of the request message that is the request body.
+func (renderer *Renderer) getRequestBody(method *surface_v1.Method) string {
+	if renderer.wholeMessageBodies[method] || method.ParametersTypeName == httpBodyTypeName {
+		return "*"
+	}
+	return getRequestBodyForRequestParameter(method.ParametersTypeName, renderer.Model.Types)
 }
 
 // getRequestBodyForRequestParameter finds the corresponding surface model type for 'name' and returns the name of the
@@ -120,6 +292,19 @@ func getRequestBodyForRequestParameter(name string, types []*surface_v1.Type) st
 	return ""
 }
 
+// getResponseBodyForResponse returns the name of the field of the response message 'name' that is the response body,
+// if that message is a wrapper for an array or scalar response (see adjustV3Model). Otherwise it returns an empty
+// string. Wrapper messages are recognized by their only field, which is named after the media type of the response.
+func getResponseBodyForResponse(name string, types []*surface_v1.Type) string {
+	for _, t := range types {
+		if t.TypeName == name && len(t.Fields) == 1 && strings.Contains(t.Fields[0].Name, "/") &&
+			isWrappedResponse(t.Fields[0]) {
+			return t.Fields[0].FieldName
+		}
+	}
+	return ""
+}
+
 func buildInputTypeAndOutputType(parametersTypeName, responseTypeName string) (inputType, outputType string) {
 	inputType = parametersTypeName
 	outputType = responseTypeName
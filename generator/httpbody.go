@@ -0,0 +1,172 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+
+	// Registers google/api/httpbody.proto for buildWellKnownDependencies.
+	_ "google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+// Request and response bodies that aren't JSON are represented by google.api.HttpBody.
+// See: https://github.com/googleapis/googleapis/blob/master/google/api/httpbody.proto
+const (
+	httpBodyTypeName  = "google.api.HttpBody"
+	httpBodyFieldName = "http_body"
+)
+
+// isHttpBodyMediaType returns true if the content of 'mediaType' can't be represented by a message and is passed
+// through as google.api.HttpBody, e.g.: "application/octet-stream", "image/png", "text/csv" or form media types.
+func isHttpBodyMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+	switch {
+	case !strings.Contains(mediaType, "/"):
+		return false
+	case mediaType == "application/json", mediaType == "text/json", mediaType == "*/*",
+		strings.HasSuffix(mediaType, "+json"):
+		return false
+	case isStreamingMediaType(mediaType):
+		return false
+	}
+	return true
+}
+
+// hasOnlyHttpBodyMediaTypes returns true if all 'mediaTypes' are passed through as google.api.HttpBody.
+func hasOnlyHttpBodyMediaTypes(mediaTypes []string) bool {
+	for _, mediaType := range mediaTypes {
+		if !isHttpBodyMediaType(mediaType) {
+			return false
+		}
+	}
+	return len(mediaTypes) > 0
+}
+
+// applyHttpBodies replaces the content of request bodies and responses that only have non-JSON media types with
+// google.api.HttpBody. The surface model holds the content of a body in a type with one field per media type, which
+// is named after the media type. Request bodies that reference the components of 'document' have no such type. This
+// function has to be called before AdjustSurfaceModel, which removes those types.
+func applyHttpBodies(model *surface_v1.Model, document *openapiv3.Document) {
+	contentTypes := make(map[string]bool)
+	referencedBodies := make(map[string]bool)
+	for _, m := range model.Methods {
+		if request := model.TypeWithTypeName(m.ParametersTypeName); request != nil {
+			for _, f := range request.Fields {
+				if f.Name != "request_body" {
+					continue
+				}
+				if hasReferencedHttpBody(document, m) {
+					// There is no intermediate type for a referenced request body, the field itself is replaced.
+					referencedBodies[f.NativeType] = true
+					f.FieldName = httpBodyFieldName
+					f.Type = httpBodyTypeName
+					f.NativeType = httpBodyTypeName
+					f.Kind = surface_v1.FieldKind_REFERENCE
+					continue
+				}
+				contentTypes[f.NativeType] = true
+			}
+		}
+		if responses := model.TypeWithTypeName(m.ResponsesTypeName); responses != nil {
+			for _, f := range responses.Fields {
+				contentTypes[f.NativeType] = true
+			}
+		}
+	}
+
+	replacedTypes := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if !contentTypes[t.TypeName] {
+			continue
+		}
+		mediaTypes := make([]string, 0)
+		for _, f := range t.Fields {
+			mediaTypes = append(mediaTypes, f.Name)
+		}
+		if !hasOnlyHttpBodyMediaTypes(mediaTypes) {
+			continue
+		}
+		for _, f := range t.Fields {
+			if replaced := model.TypeWithTypeName(f.NativeType); replaced != nil {
+				replacedTypes = append(replacedTypes, replaced)
+			}
+		}
+		t.Fields = []*surface_v1.Field{{
+			Name:       t.Fields[0].Name,
+			FieldName:  httpBodyFieldName,
+			Type:       httpBodyTypeName,
+			NativeType: httpBodyTypeName,
+			Kind:       surface_v1.FieldKind_REFERENCE,
+		}}
+	}
+	// Inline schemas of the replaced content (e.g. of forms) and referenced request bodies aren't needed anymore.
+	removeUnusedTypes(model, replacedTypes...)
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if !referencedBodies[t.TypeName] || isUsedAsField(model, t.TypeName) {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+}
+
+// isUsedAsField returns true if a field of the model has the type 'typeName'.
+func isUsedAsField(model *surface_v1.Model, typeName string) bool {
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			if f.NativeType == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasReferencedHttpBody returns true if the request body of the operation of 'method' references a request body of the
+// components of 'document', which only has media types that are passed through as google.api.HttpBody.
+func hasReferencedHttpBody(document *openapiv3.Document, method *surface_v1.Method) bool {
+	operation := findOperation(document, method.Method, method.Path)
+	if operation.GetRequestBody().GetReference() == nil {
+		return false
+	}
+	mediaTypes := make([]string, 0)
+	for _, namedMediaType := range resolveRequestBody(document, operation.GetRequestBody()).GetContent().GetAdditionalProperties() {
+		mediaTypes = append(mediaTypes, namedMediaType.Name)
+	}
+	return hasOnlyHttpBodyMediaTypes(mediaTypes)
+}
+
+// useHttpBodyRequests makes google.api.HttpBody the input of methods, whose request consists of a HttpBody only.
+// Requests that have parameters besides the body keep a field named 'http_body'. This function has to be called after
+// AdjustSurfaceModel.
+func useHttpBodyRequests(model *surface_v1.Model) {
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		if request == nil || len(request.Fields) != 1 || request.Fields[0].NativeType != httpBodyTypeName {
+			continue
+		}
+		types := make([]*surface_v1.Type, 0)
+		for _, t := range model.Types {
+			if t != request {
+				types = append(types, t)
+			}
+		}
+		model.Types = types
+		m.ParametersTypeName = httpBodyTypeName
+	}
+}
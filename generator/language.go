@@ -18,45 +18,120 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/google/gnostic-grpc/transcoding"
+	openapiv3 "github.com/google/gnostic/openapiv3"
 	surface_v1 "github.com/google/gnostic/surface"
 )
 
-type ProtoLanguageModel struct{}
+type ProtoLanguageModel struct {
+	// NamingStrategy determines the names of the generated messages, fields, enums and methods.
+	NamingStrategy NamingStrategy
+	// The OpenAPI document the surface model has been built from. It is used to read specification extensions and
+	// may be nil.
+	Document *openapiv3.Document
+	// TypeMappings maps schemas onto existing proto types. May be nil.
+	TypeMappings *TypeMappings
+	// OneofResponses makes operations with several successful responses return a oneof over those responses instead
+	// of the response with the lowest status code.
+	OneofResponses bool
+	// MetadataParameters passes header and cookie parameters as gRPC metadata instead of request fields.
+	MetadataParameters bool
+
+	// The error responses of the methods, which are collected by Prepare.
+	errorResponses map[*surface_v1.Method][]*errorResponse
+	// The types that hold a oneof over the successful responses of a method, which are created by Prepare.
+	oneofTypes map[*surface_v1.Type]string
+	// The header and cookie parameters of the methods, which are removed from the request messages by Prepare.
+	metadataParameters map[*surface_v1.Method][]*transcoding.Metadata
+}
 
 func NewProtoLanguageModel() *ProtoLanguageModel {
-	return &ProtoLanguageModel{}
+	return &ProtoLanguageModel{NamingStrategy: &DefaultNamingStrategy{}}
 }
 
 // Prepare sets language-specific properties for all types and methods.
 func (language *ProtoLanguageModel) Prepare(model *surface_v1.Model, inputDocumentType string) {
+	naming := language.NamingStrategy
+	mergeDuplicateTypes(model)
+
+	// The parameters of a method are named after the method rather than after the surface model type.
+	requestNames := make(map[string]string)
+	for _, m := range model.Methods {
+		if m.ParametersTypeName != "" {
+			requestNames[m.ParametersTypeName] = naming.RequestMessageName(m)
+		}
+	}
+
 	for _, t := range model.Types {
 		// determine the name of protocol buffer messages
-		t.TypeName = protoTypeName(strings.Replace(t.Name, "Parameters", "Request", 1))
+		t.TypeName = naming.MessageName(t.Name)
+		if requestName, ok := requestNames[t.Name]; ok {
+			t.TypeName = requestName
+		}
 
 		for _, f := range t.Fields {
-			f.FieldName = protoFieldName(f.Name, f.Type)
-			f.NativeType = findNativeType(f.Type, f.Format)
+			f.FieldName = naming.FieldName(f.Name, f.Type)
+			f.NativeType = findNativeType(f.Type, f.Format, naming)
 
 			if f.EnumValues != nil {
-				f.NativeType = strings.Title(f.Name)
+				f.NativeType = naming.EnumName(f.Name)
 			}
 		}
 	}
 
 	for _, m := range model.Methods {
-		m.HandlerName = protoTypeName(m.Name)
+		m.HandlerName = naming.MethodName(m)
 		m.ProcessorName = m.Name
 		m.ClientName = m.Name
-		m.ParametersTypeName = protoTypeName(strings.Replace(m.ParametersTypeName, "Parameters", "Request", 1))
-		m.ResponsesTypeName = protoTypeName(m.ResponsesTypeName)
+		m.ParametersTypeName = requestNames[m.ParametersTypeName]
+		m.ResponsesTypeName = naming.MessageName(m.ResponsesTypeName)
 	}
 
+	applyTypeMappings(model, language.TypeMappings, language.Document)
+	applyHttpBodies(model, language.Document)
+	flattenQueryObjects(model, language.Document)
+	if language.MetadataParameters {
+		language.metadataParameters = extractMetadataParameters(model, language.Document)
+	}
+	language.errorResponses = collectErrorResponses(model, language.Document)
+	var successResponses map[*surface_v1.Method]*successResponses
+	if language.OneofResponses {
+		successResponses = collectSuccessResponses(model, naming)
+	}
 	AdjustSurfaceModel(model, inputDocumentType)
+	useHttpBodyRequests(model)
+	language.oneofTypes = applyOneofResponses(model, successResponses)
+	applyProtoExtensions(model, newProtoExtensions(language.Document))
+	renameOperationResponses(model, naming)
+}
+
+// renameOperationResponses renames the response messages which have been generated for an operation (and not for a
+// component schema) according to 'naming'.
+func renameOperationResponses(model *surface_v1.Model, naming NamingStrategy) {
+	for _, m := range model.Methods {
+		t := model.TypeWithTypeName(m.ResponsesTypeName)
+		if t == nil || m.Operation == "" || !strings.HasPrefix(t.Name, m.Operation) {
+			continue
+		}
+		responseName := naming.ResponseMessageName(m)
+		if responseName == t.TypeName || model.TypeWithTypeName(responseName) != nil {
+			continue
+		}
+		for _, other := range model.Types {
+			for _, f := range other.Fields {
+				if f.NativeType == t.TypeName {
+					f.NativeType = responseName
+				}
+			}
+		}
+		t.TypeName = responseName
+		m.ResponsesTypeName = responseName
+	}
 }
 
 // findNativeType maps OpenAPI data types (https://swagger.io/docs/specification/data-models/data-types/)
 // to .proto types (https://developers.google.com/protocol-buffers/docs/proto3#scalar)
-func findNativeType(fType string, fFormat string) string {
+func findNativeType(fType string, fFormat string, naming NamingStrategy) string {
 	switch fType {
 	case "boolean":
 		return "bool"
@@ -112,11 +187,11 @@ func findNativeType(fType string, fFormat string) string {
 				"int64": true,
 			}
 			if !formattedType[mapType] {
-				return "map[string]" + findNativeType(mapType, "")
+				return "map[string]" + findNativeType(mapType, "", naming)
 			}
 			return fType
 		}
-		return protoTypeName(fType)
+		return naming.MessageName(fType)
 	}
 }
 
@@ -140,9 +215,12 @@ func AdjustSurfaceModel(model *surface_v1.Model, inputDocumentType string) {
 // adjustV3Model removes unnecessary types from the surface model. The original input file is an OpenAPI v2 file.
 func adjustV3Model(model *surface_v1.Model) {
 	nameToType, typesToDelete := initHashTables(model)
+	// Methods that share an operationId share their parameters, which must be adjusted only once.
+	adjustedParameters := make(map[*surface_v1.Type]bool)
 	for _, m := range model.Methods {
 		if len(m.ParametersTypeName) > 0 {
-			if parameters, ok := nameToType[m.ParametersTypeName]; ok {
+			if parameters, ok := nameToType[m.ParametersTypeName]; ok && !adjustedParameters[parameters] {
+				adjustedParameters[parameters] = true
 				// For requestBodies we remove the intermediate type.
 				for _, f := range parameters.Fields {
 					if f.Name == "request_body" {
@@ -171,7 +249,14 @@ func adjustV3Model(model *surface_v1.Model) {
 				lowestStatusCodeResponse := findLowestStatusCode(responses, nameToType)
 
 				m.ResponsesTypeName = ""
-				if lowestStatusCodeResponse != nil && lowestStatusCodeResponse.Fields[0].Kind != surface_v1.FieldKind_SCALAR {
+				if lowestStatusCodeResponse != nil && isWrappedResponse(lowestStatusCodeResponse.Fields[0]) {
+					// Arrays and scalars can't be returned directly. We keep the type of the status code as a wrapper
+					// message, whose only field becomes the response_body of the HttpRule.
+					typesToDelete[lowestStatusCodeResponse] = false
+					lowestStatusCodeResponse.Fields = lowestStatusCodeResponse.Fields[:1]
+					lowestStatusCodeResponse.Fields[0].FieldName = wrappedResponseFieldName(lowestStatusCodeResponse.Fields[0])
+					m.ResponsesTypeName = lowestStatusCodeResponse.TypeName
+				} else if lowestStatusCodeResponse != nil {
 					// We set the response with the lowest status code as response.
 					m.ResponsesTypeName = lowestStatusCodeResponse.Fields[0].NativeType
 				} else {
@@ -231,6 +316,19 @@ func adjustV2Model(model *surface_v1.Model) {
 	model.Types = filteredTypes
 }
 
+// isWrappedResponse returns true if the content 'field' of a response has to be wrapped into a message.
+func isWrappedResponse(field *surface_v1.Field) bool {
+	return field.Kind == surface_v1.FieldKind_SCALAR || field.Kind == surface_v1.FieldKind_ARRAY
+}
+
+// wrappedResponseFieldName returns the name of the field that holds the content 'field' inside a wrapper message.
+func wrappedResponseFieldName(field *surface_v1.Field) string {
+	if field.Kind == surface_v1.FieldKind_ARRAY {
+		return "items"
+	}
+	return "value"
+}
+
 // findLowestStatusCode returns a surface Type that represents the lowest status code for the given 'responses' type.
 func findLowestStatusCode(responses *surface_v1.Type, nameToType map[string]*surface_v1.Type) *surface_v1.Type {
 	if lowestStatusCodeResponse, ok := nameToType[responses.Fields[0].NativeType]; ok {
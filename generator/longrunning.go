@@ -0,0 +1,138 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+	"google.golang.org/genproto/googleapis/longrunning"
+)
+
+const (
+	operationTypeName = "google.longrunning.Operation"
+
+	// The specification extensions of an operation that set the response_type and metadata_type of its
+	// google.longrunning.operation_info option, e.g. "Book", "#/components/schemas/Book" or "google.protobuf.Empty".
+	extensionLroResponse = "x-grpc-lro-response"
+	extensionLroMetadata = "x-grpc-lro-metadata"
+)
+
+// applyLongRunningOperations makes the methods whose operation answers with 202 Accepted return a
+// google.longrunning.Operation (https://google.aip.dev/151). Operations qualify if 202 is their lowest status code and
+// the response carries a status URL (a Location header or a link) or they have one of the x-grpc-lro-* extensions.
+// The types of the operation_info option of those methods are returned:
+//   - response_type: the extension x-grpc-lro-response, otherwise the output of the polled status operation, which
+//     is the target of a link of the 202 response.
+//   - metadata_type: the extension x-grpc-lro-metadata, otherwise the content of the 202 response.
+//
+// Types that can't be determined are google.protobuf.Empty.
+func applyLongRunningOperations(renderer *Renderer) map[*surface_v1.Method]*longrunning.OperationInfo {
+	operationInfos := make(map[*surface_v1.Method]*longrunning.OperationInfo)
+	if !renderer.LongRunningOperations {
+		return operationInfos
+	}
+	for _, m := range renderer.Model.Methods {
+		operation := findOperation(renderer.Document, m.Method, m.Path)
+		accepted := findAcceptedResponse(operation)
+		if accepted == nil {
+			continue
+		}
+		extensions := operation.GetSpecificationExtension()
+		responseType, hasResponseType := stringExtension(extensions, extensionLroResponse)
+		metadataType, hasMetadataType := stringExtension(extensions, extensionLroMetadata)
+		statusOperation := findStatusOperation(renderer, accepted)
+		if !hasStatusURL(accepted) && statusOperation == nil && !hasResponseType && !hasMetadataType {
+			log.Printf("The 202 response of %s has no status URL, it isn't mapped onto a long-running operation",
+				operationKey(m.Method, m.Path))
+			continue
+		}
+
+		info := &longrunning.OperationInfo{ResponseType: "google.protobuf.Empty", MetadataType: "google.protobuf.Empty"}
+		switch {
+		case hasResponseType:
+			info.ResponseType = resolveLroType(renderer, responseType)
+		case statusOperation != nil && statusOperation.ResponsesTypeName != "":
+			info.ResponseType = statusOperation.ResponsesTypeName
+		}
+		switch {
+		case hasMetadataType:
+			info.MetadataType = resolveLroType(renderer, metadataType)
+		case m.ResponsesTypeName != "":
+			info.MetadataType = m.ResponsesTypeName
+		}
+		m.ResponsesTypeName = operationTypeName
+		operationInfos[m] = info
+	}
+	return operationInfos
+}
+
+// findAcceptedResponse returns the 202 response of 'operation' if that is its lowest status code.
+func findAcceptedResponse(operation *openapiv3.Operation) *openapiv3.Response {
+	var accepted *openapiv3.Response
+	lowest := 0
+	for _, namedResponse := range operation.GetResponses().GetResponseOrReference() {
+		statusCode, err := strconv.Atoi(namedResponse.Name)
+		if err != nil || (lowest != 0 && statusCode >= lowest) {
+			continue
+		}
+		lowest, accepted = statusCode, namedResponse.GetValue().GetResponse()
+	}
+	if lowest != 202 {
+		return nil
+	}
+	return accepted
+}
+
+// hasStatusURL returns true if 'response' has a Location header.
+func hasStatusURL(response *openapiv3.Response) bool {
+	for _, namedHeader := range response.GetHeaders().GetAdditionalProperties() {
+		if strings.EqualFold(namedHeader.Name, "Location") {
+			return true
+		}
+	}
+	return false
+}
+
+// findStatusOperation returns the method of the operation that a link of 'response' points to by its operationId.
+func findStatusOperation(renderer *Renderer, response *openapiv3.Response) *surface_v1.Method {
+	for _, namedLink := range response.GetLinks().GetAdditionalProperties() {
+		operationId := namedLink.GetValue().GetLink().GetOperationId()
+		for _, m := range renderer.Model.Methods {
+			if operationId != "" && m.Operation == operationId {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// resolveLroType returns the name of the message of 'value', which is the name of a component schema, a reference
+// to it or a fully qualified proto type.
+func resolveLroType(renderer *Renderer, value string) string {
+	name := strings.TrimPrefix(value, "#/components/schemas/")
+	for _, t := range renderer.Model.Types {
+		if t.Name == name {
+			return t.TypeName
+		}
+	}
+	if strings.Contains(name, ".") {
+		return strings.TrimPrefix(name, ".")
+	}
+	return renderer.NamingStrategy.MessageName(name)
+}
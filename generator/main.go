@@ -16,11 +16,13 @@ package generator
 
 import (
 	"errors"
+	"fmt"
 	"go/format"
 	"path/filepath"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/google/gnostic-grpc/utils"
 	openapiv3 "github.com/google/gnostic/openapiv3"
 	plugins "github.com/google/gnostic/plugins"
 	surface "github.com/google/gnostic/surface"
@@ -31,15 +33,19 @@ func RunProtoGenerator(env *plugins.Environment) {
 	fileName := getFilenameWithoutFileExtension(env)
 	packageName, err := resolvePackageName(fileName)
 	env.RespondAndExitIfError(err)
+	parameters, err := parseParameters(env.Request.Parameters)
+	env.RespondAndExitIfError(err)
 
 	inputDocumentType := env.Request.Models[0].TypeUrl
+	var openAPIdocument *openapiv3.Document
 	for _, model := range env.Request.Models {
 		switch model.TypeUrl {
 		case "openapi.v3.Document":
-			openAPIdocument := &openapiv3.Document{}
-			err := proto.Unmarshal(model.Value, openAPIdocument)
+			document := &openapiv3.Document{}
+			err := proto.Unmarshal(model.Value, document)
 
 			if err == nil {
+				openAPIdocument = document
 				featureChecker := NewGrpcChecker(openAPIdocument)
 				env.Response.Messages = featureChecker.Run()
 			}
@@ -47,15 +53,13 @@ func RunProtoGenerator(env *plugins.Environment) {
 			surfaceModel := &surface.Model{}
 			err = proto.Unmarshal(model.Value, surfaceModel)
 			if err == nil {
-				// Customizes the surface model for a .proto output file
-				NewProtoLanguageModel().Prepare(surfaceModel, inputDocumentType)
-
-				// Create the renderer.
-				renderer := NewRenderer(surfaceModel)
-				renderer.Package = packageName
+				// Customizes the surface model for a .proto output file and creates the renderer.
+				renderer, err := newRendererForParameters(surfaceModel, openAPIdocument, env.Request.SourceName,
+					inputDocumentType, packageName, parameters)
+				env.RespondAndExitIfError(err)
 
 				// Run the renderer to generate files and add them to the response object.
-				err = renderer.Render(env.Response, packageName+".proto")
+				err = renderer.Render(env.Response, protoFilePath(renderer.Package))
 				env.RespondAndExitIfError(err)
 				// Return with success.
 				env.RespondAndExit()
@@ -66,6 +70,301 @@ func RunProtoGenerator(env *plugins.Environment) {
 	env.RespondAndExitIfError(err)
 }
 
+// generatorParameters holds the values of the plugin parameters that customize the generated output, e.g.:
+//
+//	gnostic --grpc-out=naming=aip:. bookstore.yaml
+type generatorParameters struct {
+	namingStrategy     NamingStrategy
+	typeMappings       *TypeMappings
+	servicesPerTag     bool
+	wholeMessageBodies bool
+	oneofResponses     bool
+	metadataParameters bool
+	inferResources     bool
+	detectPagination   bool
+	paginationFields   bool
+	updateMasks        bool
+	longRunning        bool
+	renameDuplicates   bool
+	fileOptions        *FileOptions
+	versionSource      string
+	prefixBasePath     bool
+	stripBasePath      bool
+	serviceConfig      string
+	mergeSignatures    bool
+	errorComments      bool
+}
+
+// parseParameters validates the plugin parameters and converts them to generatorParameters.
+func parseParameters(parameters []*plugins.Parameter) (*generatorParameters, error) {
+	result := &generatorParameters{namingStrategy: &DefaultNamingStrategy{}, fileOptions: &FileOptions{}}
+	for _, p := range parameters {
+		switch p.Name {
+		case "naming":
+			result.namingStrategy = NewNamingStrategy(p.Value)
+			if result.namingStrategy == nil {
+				return nil, fmt.Errorf("unsupported value for parameter naming: %s", p.Value)
+			}
+		case "type_mappings":
+			typeMappings, err := LoadTypeMappings(p.Value)
+			if err != nil {
+				return nil, err
+			}
+			result.typeMappings = typeMappings
+		case "services":
+			switch p.Value {
+			case "single":
+				result.servicesPerTag = false
+			case "tags":
+				result.servicesPerTag = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter services: %s", p.Value)
+			}
+		case "request_body":
+			switch p.Value {
+			case "field":
+				result.wholeMessageBodies = false
+			case "whole_message":
+				result.wholeMessageBodies = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter request_body: %s", p.Value)
+			}
+		case "responses":
+			switch p.Value {
+			case "lowest":
+				result.oneofResponses = false
+			case "oneof":
+				result.oneofResponses = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter responses: %s", p.Value)
+			}
+		case "headers":
+			switch p.Value {
+			case "field":
+				result.metadataParameters = false
+			case "metadata":
+				result.metadataParameters = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter headers: %s", p.Value)
+			}
+		case "resources":
+			switch p.Value {
+			case "none":
+				result.inferResources = false
+			case "infer":
+				result.inferResources = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter resources: %s", p.Value)
+			}
+		case "pagination":
+			switch p.Value {
+			case "none":
+				result.detectPagination, result.paginationFields = false, false
+			case "report":
+				result.detectPagination, result.paginationFields = true, false
+			case "aip":
+				result.detectPagination, result.paginationFields = true, true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter pagination: %s", p.Value)
+			}
+		case "patch":
+			switch p.Value {
+			case "message":
+				result.updateMasks = false
+			case "update_mask":
+				result.updateMasks = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter patch: %s", p.Value)
+			}
+		case "accepted":
+			switch p.Value {
+			case "response":
+				result.longRunning = false
+			case "operation":
+				result.longRunning = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter accepted: %s", p.Value)
+			}
+		case "duplicates":
+			switch p.Value {
+			case "fail":
+				result.renameDuplicates = false
+			case "rename":
+				result.renameDuplicates = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter duplicates: %s", p.Value)
+			}
+		case "version":
+			switch p.Value {
+			case "none":
+				result.versionSource = ""
+			case versionFromInfo, versionFromServer:
+				result.versionSource = p.Value
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter version: %s", p.Value)
+			}
+		case "base_path":
+			switch p.Value {
+			case "ignore":
+				result.prefixBasePath, result.stripBasePath = false, false
+			case "prefix":
+				result.prefixBasePath, result.stripBasePath = true, false
+			case "strip":
+				result.prefixBasePath, result.stripBasePath = false, true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter base_path: %s", p.Value)
+			}
+		case "service_config":
+			switch p.Value {
+			case "none":
+				result.serviceConfig = ""
+			case serviceConfigYAML, serviceConfigJSON:
+				result.serviceConfig = p.Value
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter service_config: %s", p.Value)
+			}
+		case "bindings":
+			switch p.Value {
+			case "explicit":
+				result.mergeSignatures = false
+			case "signatures":
+				result.mergeSignatures = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter bindings: %s", p.Value)
+			}
+		case "errors":
+			switch p.Value {
+			case "none":
+				result.errorComments = false
+			case "comment":
+				result.errorComments = true
+			default:
+				return nil, fmt.Errorf("unsupported value for parameter errors: %s", p.Value)
+			}
+		case "file_options":
+			fileOptions, err := LoadFileOptions(p.Value)
+			if err != nil {
+				return nil, err
+			}
+			result.fileOptions.merge(fileOptions)
+		default:
+			if !utils.Contains(fileOptionParameters, p.Name) {
+				return nil, fmt.Errorf("unsupported parameter name: %s", p.Name)
+			}
+			if err := result.fileOptions.set(p.Name, p.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// newRendererForParameters prepares 'model' for a .proto output file and creates a renderer for it. Both are
+// customized according to 'parameters' and the specification extensions of 'document', which may be nil. The model
+// is rebuilt from 'document', which has been read from 'sourceName', if operations have to be named or renamed.
+func newRendererForParameters(model *surface.Model, document *openapiv3.Document, sourceName string,
+	inputDocumentType string, packageName string, parameters *generatorParameters) (*Renderer, error) {
+	// The package of the parameters overrides the one of the document, which overrides the one of the file name.
+	packageNames := []string{newProtoExtensions(document).packageName}
+	if parameters.fileOptions != nil {
+		packageNames = append(packageNames, parameters.fileOptions.Package)
+	}
+	for _, name := range packageNames {
+		if name == "" {
+			continue
+		}
+		if err := validateProtoPackageName(name); err != nil {
+			return nil, err
+		}
+		packageName = name
+	}
+	if parameters.versionSource != "" {
+		version, err := apiVersion(document, parameters.versionSource)
+		if err != nil {
+			return nil, err
+		}
+		packageName = versionedPackageName(packageName, version)
+	}
+
+	// Names the operations without operationId independently of the gnostic version.
+	model, err := applySynthesizedOperationIds(model, document, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	language := prepareModel(model, document, inputDocumentType, parameters)
+	renamedOperations := make([]*plugins.Message, 0)
+	for round := 0; ; round++ {
+		duplicates := findDuplicateSymbols(model, document, parameters.servicesPerTag, parameters.mergeSignatures)
+		if len(duplicates) == 0 {
+			break
+		}
+		locator := newSymbolLocator(sourceName)
+		if !parameters.renameDuplicates || document == nil || round == maxRenameRounds {
+			return nil, newDuplicateSymbolsError(duplicates, locator)
+		}
+		messages, ok := renameDuplicateOperations(document, duplicates, locator)
+		if !ok {
+			return nil, newDuplicateSymbolsError(duplicates, locator)
+		}
+		renamedOperations = append(renamedOperations, messages...)
+		if model, err = surface.NewModelFromOpenAPI3(document, sourceName); err != nil {
+			return nil, err
+		}
+		language = prepareModel(model, document, inputDocumentType, parameters)
+	}
+
+	renderer := NewRenderer(model)
+	renderer.SourceName = sourceName
+	renderer.Package = packageName
+	renderer.FileOptions = parameters.fileOptions
+	renderer.NamingStrategy = parameters.namingStrategy
+	renderer.Document = document
+	renderer.TypeMappings = parameters.typeMappings
+	renderer.ServicesPerTag = parameters.servicesPerTag
+	renderer.WholeMessageBodies = parameters.wholeMessageBodies
+	renderer.OneofResponses = parameters.oneofResponses
+	renderer.MetadataParameters = parameters.metadataParameters
+	renderer.InferResources = parameters.inferResources
+	renderer.DetectPagination = parameters.detectPagination
+	renderer.PaginationFields = parameters.paginationFields
+	renderer.UpdateMasks = parameters.updateMasks
+	renderer.LongRunningOperations = parameters.longRunning
+	renderer.PrefixBasePath = parameters.prefixBasePath
+	renderer.StripBasePath = parameters.stripBasePath
+	renderer.ServiceConfig = parameters.serviceConfig
+	renderer.MergeIdenticalSignatures = parameters.mergeSignatures
+	renderer.ErrorComments = parameters.errorComments
+	renderer.errorResponses = language.errorResponses
+	renderer.oneofTypes = language.oneofTypes
+	renderer.metadataParameters = language.metadataParameters
+	renderer.renamedOperations = renamedOperations
+	return renderer, nil
+}
+
+// prepareModel customizes 'model' for a .proto output file according to 'parameters' and returns the language model
+// that holds what has been extracted from it.
+func prepareModel(model *surface.Model, document *openapiv3.Document, inputDocumentType string,
+	parameters *generatorParameters) *ProtoLanguageModel {
+	language := NewProtoLanguageModel()
+	language.NamingStrategy = parameters.namingStrategy
+	language.Document = document
+	language.TypeMappings = parameters.typeMappings
+	language.OneofResponses = parameters.oneofResponses
+	language.MetadataParameters = parameters.metadataParameters
+	language.Prepare(model, inputDocumentType)
+	return language
+}
+
+// validateProtoPackageName returns an error if 'name' is not a valid (possibly dot-separated) proto package name.
+func validateProtoPackageName(name string) error {
+	for _, part := range strings.Split(name, ".") {
+		if _, err := format.Source([]byte("package " + part)); err != nil || part == "" {
+			return errors.New("invalid package name " + name)
+		}
+	}
+	return nil
+}
+
 // resolvePackageName converts a path to a valid package name or
 // error if path can't be resolved or resolves to an invalid package name.
 func resolvePackageName(p string) (string, error) {
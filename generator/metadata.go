@@ -0,0 +1,124 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/transcoding"
+	"github.com/google/gnostic-grpc/utils"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// The file that defines the (gnostic.grpc.metadata) option.
+const metadataOptionFile = "transcoding/options.proto"
+
+// extractMetadataParameters removes the header and cookie parameters from the request messages of the model, because
+// gRPC HTTP/JSON transcoders don't populate request fields from them. The parameters of each method are returned, they
+// are passed as gRPC metadata instead. Request messages without other fields are removed.
+func extractMetadataParameters(model *surface_v1.Model, document *openapiv3.Document) map[*surface_v1.Method][]*transcoding.Metadata {
+	result := make(map[*surface_v1.Method][]*transcoding.Metadata)
+	referencedTypes := make([]string, 0)
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		if request == nil {
+			continue
+		}
+		parameters := findParameters(document, findOperation(document, m.Method, m.Path), "header", "cookie")
+		fields := make([]*surface_v1.Field, 0)
+		for _, f := range request.Fields {
+			parameter, ok := parameters[f.Name]
+			switch {
+			case ok && f.Name != "request_body":
+				result[m] = append(result[m], newMetadata(parameter))
+				referencedTypes = append(referencedTypes, f.NativeType)
+			case f.Position == surface_v1.Position_HEADER:
+				// A header parameter that isn't part of the document, e.g. of a model without document.
+				result[m] = append(result[m], &transcoding.Metadata{Name: f.Name, Type: f.Type})
+			default:
+				fields = append(fields, f)
+			}
+		}
+		if len(result[m]) == 0 {
+			continue
+		}
+		request.Fields = fields
+		if len(fields) == 0 {
+			m.ParametersTypeName = ""
+			removeUnusedTypes(model, request)
+		}
+	}
+
+	// The messages of referenced parameters aren't needed anymore.
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if !utils.Contains(referencedTypes, t.TypeName) || isUsedAsField(model, t.TypeName) {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+	return result
+}
+
+// newMetadata returns the description of the header or cookie 'parameter'.
+func newMetadata(parameter *openapiv3.Parameter) *transcoding.Metadata {
+	metadata := &transcoding.Metadata{
+		Name:     parameter.Name,
+		Type:     parameter.GetSchema().GetSchema().GetType(),
+		Required: parameter.Required,
+	}
+	if metadata.Type == "" {
+		metadata.Type = "string"
+	}
+	if parameter.In == "cookie" {
+		metadata.Source = transcoding.Metadata_COOKIE
+	}
+	return metadata
+}
+
+// buildMetadataComment documents the header and cookie parameters of a method, e.g.:
+//
+//	gRPC metadata (gnostic.grpc.metadata):
+//	  - X-Request-Id: string (header, required)
+//	  - session: string (cookie)
+func buildMetadataComment(parameters []*transcoding.Metadata) string {
+	if len(parameters) == 0 {
+		return ""
+	}
+	lines := []string{"gRPC metadata (gnostic.grpc.metadata):"}
+	for _, parameter := range parameters {
+		details := []string{strings.ToLower(parameter.Source.String())}
+		if parameter.Required {
+			details = append(details, "required")
+		}
+		lines = append(lines, "  - "+parameter.Name+": "+parameter.Type+" ("+strings.Join(details, ", ")+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildMetadataDependencies returns the FileDescriptorProto that defines the (gnostic.grpc.metadata) option and the
+// name of the file that has to be imported, if a method of 'renderer' passes parameters as metadata.
+func buildMetadataDependencies(renderer *Renderer, imports []string) (dependencies []*dpb.FileDescriptorProto, newImports []string, err error) {
+	if len(renderer.metadataParameters) == 0 || utils.Contains(imports, metadataOptionFile) {
+		return nil, nil, nil
+	}
+	dependencies, err = loadFileDescriptorProtos(metadataOptionFile, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dependencies, []string{metadataOptionFile}, nil
+}
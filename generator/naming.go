@@ -0,0 +1,118 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// NamingStrategy determines the names of the messages, fields, enums, methods and services inside the generated
+// .proto file. Library users can provide their own implementation to enforce the naming rules of their organization.
+type NamingStrategy interface {
+	// MessageName returns the name of the message that is generated for the surface model type 'name'.
+	MessageName(name string) string
+	// RequestMessageName returns the name of the message that holds the parameters of 'method'.
+	RequestMessageName(method *surface_v1.Method) string
+	// ResponseMessageName returns the name of the message that is returned by 'method', if that message has been
+	// generated for the operation itself (i.e. it is not a component schema).
+	ResponseMessageName(method *surface_v1.Method) string
+	// FieldName returns the name of the field 'name' with the OpenAPI type 'fieldType'.
+	FieldName(name string, fieldType string) string
+	// EnumName returns the name of the enum that is generated for the field 'fieldName'.
+	EnumName(fieldName string) string
+	// MethodName returns the name of the RPC method for 'method'.
+	MethodName(method *surface_v1.Method) string
+	// ServiceName returns the name of the service that is generated for 'name', which is either the name of the
+	// package or of an OpenAPI tag.
+	ServiceName(name string) string
+}
+
+// NewNamingStrategy returns the built-in naming strategy called 'name'. Valid names are "default" and "aip". It
+// returns nil if there is no such strategy.
+func NewNamingStrategy(name string) NamingStrategy {
+	switch name {
+	case "", "default":
+		return &DefaultNamingStrategy{}
+	case "aip":
+		return &AIPNamingStrategy{}
+	}
+	return nil
+}
+
+// DefaultNamingStrategy names messages after the OpenAPI description and renames "Parameters" to "Request".
+type DefaultNamingStrategy struct{}
+
+func (*DefaultNamingStrategy) MessageName(name string) string {
+	return protoTypeName(strings.Replace(name, "Parameters", "Request", 1))
+}
+
+func (*DefaultNamingStrategy) RequestMessageName(method *surface_v1.Method) string {
+	return protoTypeName(strings.Replace(method.ParametersTypeName, "Parameters", "Request", 1))
+}
+
+func (*DefaultNamingStrategy) ResponseMessageName(method *surface_v1.Method) string {
+	return method.ResponsesTypeName
+}
+
+func (*DefaultNamingStrategy) FieldName(name string, fieldType string) string {
+	return protoFieldName(name, fieldType)
+}
+
+func (*DefaultNamingStrategy) EnumName(fieldName string) string {
+	return strings.Title(fieldName)
+}
+
+func (*DefaultNamingStrategy) MethodName(method *surface_v1.Method) string {
+	return protoTypeName(method.Name)
+}
+
+func (*DefaultNamingStrategy) ServiceName(name string) string {
+	return strings.Title(CleanName(name))
+}
+
+// AIPNamingStrategy names messages and services according to the Google API Improvement Proposals, e.g.: the RPC
+// GetBook takes a GetBookRequest and ListBooks returns a ListBooksResponse.
+// See: https://google.aip.dev/131 and https://google.aip.dev/132
+type AIPNamingStrategy struct{}
+
+func (*AIPNamingStrategy) MessageName(name string) string {
+	return protoTypeName(name)
+}
+
+func (s *AIPNamingStrategy) RequestMessageName(method *surface_v1.Method) string {
+	return s.MethodName(method) + "Request"
+}
+
+func (s *AIPNamingStrategy) ResponseMessageName(method *surface_v1.Method) string {
+	return s.MethodName(method) + "Response"
+}
+
+func (*AIPNamingStrategy) FieldName(name string, fieldType string) string {
+	return protoFieldName(name, fieldType)
+}
+
+func (*AIPNamingStrategy) EnumName(fieldName string) string {
+	return toCamelCase(CleanName(fieldName))
+}
+
+func (*AIPNamingStrategy) MethodName(method *surface_v1.Method) string {
+	return protoTypeName(method.Name)
+}
+
+func (*AIPNamingStrategy) ServiceName(name string) string {
+	return toCamelCase(CleanName(name)) + "Service"
+}
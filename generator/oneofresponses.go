@@ -0,0 +1,124 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// The name of the oneof that holds the successful responses of an operation.
+const oneofResponseName = "response"
+
+// successResponses holds the successful responses of a method, which become the fields of a oneof.
+type successResponses struct {
+	// The type that holds the responses of all status codes, which is reused for the oneof.
+	responses *surface_v1.Type
+	// One field per distinct schema of the 2xx responses, named by status code.
+	fields []*surface_v1.Field
+}
+
+// collectSuccessResponses returns the methods that have several 2xx responses with distinct schemas. It has to be
+// called before AdjustSurfaceModel, which only keeps the response with the lowest status code.
+func collectSuccessResponses(model *surface_v1.Model, naming NamingStrategy) map[*surface_v1.Method]*successResponses {
+	result := make(map[*surface_v1.Method]*successResponses)
+	for _, m := range model.Methods {
+		responses := model.TypeWithTypeName(m.ResponsesTypeName)
+		if responses == nil {
+			continue
+		}
+		fields := make([]*surface_v1.Field, 0)
+		nativeTypes := make(map[string]bool)
+		isMessage := true
+		for _, f := range responses.Fields {
+			statusCode, err := strconv.Atoi(f.Name)
+			if err != nil || statusCode < 200 || statusCode >= 300 {
+				continue
+			}
+			content := successResponseContent(model, f)
+			if content == nil || nativeTypes[content.NativeType] {
+				continue
+			}
+			nativeTypes[content.NativeType] = true
+			isMessage = isMessage && content.Kind == surface_v1.FieldKind_REFERENCE
+			content.Name = f.Name
+			content.FieldName = naming.FieldName(statusFieldName(statusCode), "")
+			content.Position = surface_v1.Position_BODY
+			fields = append(fields, content)
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		if !isMessage {
+			// Members of a oneof can't be repeated, so arrays would need wrapper messages.
+			log.Printf("The successful responses of %s aren't all messages, only the lowest status code is returned",
+				operationKey(m.Method, m.Path))
+			continue
+		}
+		result[m] = &successResponses{responses: responses, fields: fields}
+	}
+	return result
+}
+
+// successResponseContent returns a copy of the field that holds the content of the response 'f' or nil if the
+// response has no content.
+func successResponseContent(model *surface_v1.Model, f *surface_v1.Field) *surface_v1.Field {
+	status := model.TypeWithTypeName(f.NativeType)
+	if status == nil {
+		return nil
+	}
+	if len(status.Fields) > 0 && strings.Contains(status.Fields[0].Name, "/") {
+		// OpenAPI v3: The type of the status code has one field per media type.
+		return proto.Clone(status.Fields[0]).(*surface_v1.Field)
+	}
+	return &surface_v1.Field{
+		Type:       status.TypeName,
+		NativeType: status.TypeName,
+		Kind:       surface_v1.FieldKind_REFERENCE,
+	}
+}
+
+// statusFieldName returns the name of the field for the status code 'statusCode', e.g.: "created" for 201.
+func statusFieldName(statusCode int) string {
+	text := http.StatusText(statusCode)
+	if text == "" {
+		return "status_" + strconv.Itoa(statusCode)
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_").Replace(text))
+}
+
+// applyOneofResponses makes the methods of 'responses' return a message with a oneof over their successful responses.
+// The types holding the oneofs are returned. This function has to be called after AdjustSurfaceModel.
+func applyOneofResponses(model *surface_v1.Model, responses map[*surface_v1.Method]*successResponses) map[*surface_v1.Type]string {
+	oneofTypes := make(map[*surface_v1.Type]string)
+	for _, m := range model.Methods {
+		success, ok := responses[m]
+		if !ok {
+			continue
+		}
+		success.responses.Fields = success.fields
+		if _, ok := oneofTypes[success.responses]; !ok {
+			model.Types = append(model.Types, success.responses)
+		}
+		oneofTypes[success.responses] = oneofResponseName
+		m.ResponsesTypeName = success.responses.TypeName
+	}
+	return oneofTypes
+}
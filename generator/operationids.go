@@ -0,0 +1,162 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strconv"
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface "github.com/google/gnostic/surface"
+)
+
+// synthesizedOperation is an operation without operationId and the name that has been synthesized for it.
+type synthesizedOperation struct {
+	operation *openapiv3.Operation
+	// The path and the lower case HTTP method of the operation, e.g.: "/shelves/{shelf}/books" and "get".
+	path, method string
+	name         string
+}
+
+// synthesizeOperationIds returns names for the operations of 'document' that don't have an operationId. The names are
+// derived from the HTTP method and the path, e.g.:
+//
+//	GET /shelves/{shelf}/books          -> ListShelfBooks
+//	GET /shelves/{shelf}/books/{book}   -> GetShelfBook
+//	POST /shelves/{shelf}/books         -> CreateShelfBook
+//	POST /shelves/{shelf}/books:archive -> ArchiveShelfBooks
+//
+// Names that collide with an operationId or a previously synthesized name get the suffix 2, 3, ... in the order of
+// the paths of the document, so they only depend on the document.
+func synthesizeOperationIds(document *openapiv3.Document) []*synthesizedOperation {
+	result := make([]*synthesizedOperation, 0)
+	usedNames := make(map[string]bool)
+	for _, namedPath := range document.GetPaths().GetPath() {
+		operations, methods := getValidOperations(namedPath.Value)
+		for _, operation := range operations {
+			if operation.OperationId != "" {
+				usedNames[strings.ToLower(operation.OperationId)] = true
+			}
+		}
+		for i, operation := range operations {
+			if operation.OperationId == "" {
+				result = append(result, &synthesizedOperation{operation: operation, path: namedPath.Name, method: methods[i]})
+			}
+		}
+	}
+	for _, synthesized := range result {
+		name := operationNameForPath(synthesized.method, synthesized.path)
+		synthesized.name = name
+		for i := 2; usedNames[strings.ToLower(synthesized.name)]; i++ {
+			synthesized.name = name + strconv.Itoa(i)
+		}
+		usedNames[strings.ToLower(synthesized.name)] = true
+	}
+	return result
+}
+
+// operationNameForPath returns the name of the operation with the HTTP method 'method' on 'path': A verb that depends
+// on the method and on whether the path ends with a collection, followed by the singular names of the parent
+// collections and the name of the last segment.
+func operationNameForPath(method string, path string) string {
+	path = strings.Trim(path, "/")
+	customVerb := ""
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") && i > strings.LastIndex(path, "}") {
+		path, customVerb = path[:i], path[i+1:]
+	}
+	segments := make([]string, 0)
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	nouns := make([]string, 0)
+	for i, segment := range segments {
+		switch {
+		case isPathVariable(segment) && (i == 0 || isPathVariable(segments[i-1])):
+			// A variable that doesn't follow a collection is named after itself.
+			nouns = append(nouns, operationNamePart(strings.Trim(segment, "{}")))
+		case isPathVariable(segment):
+			// The variable identifies an element of the previous collection.
+			nouns[len(nouns)-1] = operationNamePart(singular(segments[i-1]))
+		default:
+			nouns = append(nouns, operationNamePart(segment))
+		}
+	}
+	if len(nouns) == 0 {
+		nouns = append(nouns, "Root")
+	}
+
+	last := ""
+	if len(segments) > 0 {
+		last = segments[len(segments)-1]
+	}
+	isCollection := last != "" && !isPathVariable(last) && singular(last) != last
+	verb := operationNamePart(strings.ToLower(method))
+	switch {
+	case customVerb != "":
+		verb = operationNamePart(customVerb)
+	case method == "get" && isCollection:
+		verb = "List"
+	case method == "post" && isCollection:
+		verb = "Create"
+		nouns[len(nouns)-1] = operationNamePart(singular(last))
+	case method == "put":
+		verb = "Replace"
+	case method == "patch":
+		verb = "Update"
+	case method == "delete":
+		verb = "Delete"
+	}
+	return verb + strings.Join(nouns, "")
+}
+
+// operationNamePart converts a segment of a path into upper camel case, e.g.: "user-profiles" -> "UserProfiles".
+func operationNamePart(segment string) string {
+	name := toCamelCase(CleanName(segment))
+	return strings.Replace(name, "_", "", -1)
+}
+
+// singular returns the singular of the English plural 'word' according to the most common rules or 'word' itself.
+func singular(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(lower, "lves"):
+		return word[:len(word)-3] + "f"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"), strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && len(word) > 1:
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// applySynthesizedOperationIds sets the synthesized operationIds of the operations of 'document' and rebuilds 'model'
+// from it, so that the names of the methods and their types don't depend on how gnostic names anonymous operations.
+// 'model' is returned as is if every operation has an operationId.
+func applySynthesizedOperationIds(model *surface.Model, document *openapiv3.Document, sourceName string) (*surface.Model, error) {
+	synthesized := synthesizeOperationIds(document)
+	if len(synthesized) == 0 {
+		return model, nil
+	}
+	for _, s := range synthesized {
+		s.operation.OperationId = s.name
+	}
+	return surface.NewModelFromOpenAPI3(document, sourceName)
+}
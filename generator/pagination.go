@@ -0,0 +1,179 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/utils"
+	plugins "github.com/google/gnostic/plugins"
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// The names of the AIP-158 (https://google.aip.dev/158) pagination fields.
+const (
+	pageSizeFieldName      = "page_size"
+	pageTokenFieldName     = "page_token"
+	nextPageTokenFieldName = "next_page_token"
+)
+
+// The names of the query parameters and response properties of the pagination patterns. They are compared in lower
+// case without underscores and dashes.
+var (
+	pageSizeNames      = []string{"limit", "pagesize", "perpage", "maxresults", "size"}
+	offsetNames        = []string{"offset", "skip", "start"}
+	pageNumberNames    = []string{"page", "pagenumber"}
+	cursorNames        = []string{"cursor", "pagetoken", "after", "startingafter", "continuationtoken"}
+	nextPageTokenNames = []string{"next", "nextcursor", "nextpagetoken", "nexttoken", "continuationtoken", "cursor"}
+)
+
+// paginatedMethod describes the pagination of a list method.
+type paginatedMethod struct {
+	method *surface_v1.Method
+	// "offset" (limit/offset), "page" (page/per_page) or "cursor".
+	style string
+	// The fields of the request message.
+	pageSize, pageToken *surface_v1.Field
+	// The fields of the response message, nextPageToken is nil unless the style is "cursor".
+	items, nextPageToken *surface_v1.Field
+}
+
+// paginationAnnotations holds the paginated methods and the JSON names of the renamed fields.
+type paginationAnnotations struct {
+	methods []*paginatedMethod
+	// The original names of the renamed fields, keyed by message and field name.
+	jsonNames map[string]map[string]string
+}
+
+// detectPagination finds the GET methods of 'renderer' whose query parameters and response follow one of the common
+// pagination patterns. If 'rename' is set, the fields are renamed according to AIP-158 if their types match, their
+// JSON names keep the original names.
+func detectPagination(renderer *Renderer, rename bool) *paginationAnnotations {
+	result := &paginationAnnotations{jsonNames: make(map[string]map[string]string)}
+	model := renderer.Model
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		response := model.TypeWithTypeName(m.ResponsesTypeName)
+		if m.Method != "GET" || request == nil || response == nil {
+			continue
+		}
+		paginated := &paginatedMethod{method: m}
+		var offset, pageNumber, cursor *surface_v1.Field
+		for _, f := range request.Fields {
+			if f.Position != surface_v1.Position_QUERY {
+				continue
+			}
+			switch name := normalizePaginationName(f.Name); {
+			case utils.Contains(pageSizeNames, name):
+				paginated.pageSize = f
+			case utils.Contains(offsetNames, name):
+				offset = f
+			case utils.Contains(pageNumberNames, name):
+				pageNumber = f
+			case utils.Contains(cursorNames, name):
+				cursor = f
+			}
+		}
+		for _, f := range response.Fields {
+			switch {
+			case f.Kind == surface_v1.FieldKind_ARRAY && paginated.items == nil:
+				paginated.items = f
+			case f.Kind == surface_v1.FieldKind_SCALAR && utils.Contains(nextPageTokenNames, normalizePaginationName(f.Name)):
+				paginated.nextPageToken = f
+			}
+		}
+
+		switch {
+		case paginated.items == nil:
+			continue
+		case cursor != nil && paginated.nextPageToken != nil:
+			paginated.style, paginated.pageToken = "cursor", cursor
+		case offset != nil && paginated.pageSize != nil:
+			paginated.style, paginated.pageToken, paginated.nextPageToken = "offset", offset, nil
+		case pageNumber != nil:
+			paginated.style, paginated.pageToken, paginated.nextPageToken = "page", pageNumber, nil
+		default:
+			continue
+		}
+		result.methods = append(result.methods, paginated)
+		if rename {
+			result.renameField(request, paginated.pageSize, pageSizeFieldName, "int32", "int64")
+			if paginated.style == "cursor" {
+				result.renameField(request, paginated.pageToken, pageTokenFieldName, "string")
+				result.renameField(response, paginated.nextPageToken, nextPageTokenFieldName, "string")
+			}
+		}
+	}
+	return result
+}
+
+// renameField renames 'field' of 't' to 'name' if it has one of 'nativeTypes' and 't' has no other field of that
+// name. The original name becomes the JSON name of the field.
+func (p *paginationAnnotations) renameField(t *surface_v1.Type, field *surface_v1.Field, name string, nativeTypes ...string) {
+	if field == nil || field.FieldName == name || !utils.Contains(nativeTypes, field.NativeType) {
+		return
+	}
+	for _, f := range t.Fields {
+		if f.FieldName == name {
+			return
+		}
+	}
+	field.FieldName = name
+	if p.jsonNames[t.TypeName] == nil {
+		p.jsonNames[t.TypeName] = make(map[string]string)
+	}
+	p.jsonNames[t.TypeName][name] = field.Name
+}
+
+// setJsonName sets the JSON name of 'field' of 'message' if it has been renamed.
+func (p *paginationAnnotations) setJsonName(message *dpb.DescriptorProto, field *dpb.FieldDescriptorProto) {
+	if p == nil || p.jsonNames[message.GetName()][field.GetName()] == "" {
+		return
+	}
+	jsonName := p.jsonNames[message.GetName()][field.GetName()]
+	field.JsonName = &jsonName
+}
+
+// summary reports the paginated methods, e.g.:
+//
+//	ListBooks is paginated by cursor: page size "limit", page token "cursor", items "books", next page token "next".
+func (p *paginationAnnotations) summary() []*plugins.Message {
+	if p == nil {
+		return nil
+	}
+	messages := make([]*plugins.Message, 0)
+	for _, paginated := range p.methods {
+		parts := make([]string, 0)
+		if paginated.pageSize != nil {
+			parts = append(parts, "page size \""+paginated.pageSize.Name+"\"")
+		}
+		parts = append(parts, "page token \""+paginated.pageToken.Name+"\"", "items \""+paginated.items.FieldName+"\"")
+		if paginated.nextPageToken != nil {
+			parts = append(parts, "next page token \""+paginated.nextPageToken.Name+"\"")
+		}
+		messages = append(messages, &plugins.Message{
+			Code:  "PAGINATION",
+			Level: plugins.Message_INFO,
+			Text:  paginated.method.HandlerName + " is paginated by " + paginated.style + ": " + strings.Join(parts, ", ") + ".",
+			Keys:  []string{"paths", paginated.method.Path, strings.ToLower(paginated.method.Method)},
+		})
+	}
+	return messages
+}
+
+func normalizePaginationName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// PathTemplateError is returned if the path of an operation can't be expressed as a HttpRule path template.
+// See: https://github.com/googleapis/googleapis/blob/master/google/api/http.proto
+type PathTemplateError struct {
+	// The HTTP method of the operation, e.g.: "GET".
+	Method string
+	// The OpenAPI path of the operation, e.g.: "/files/{name}.{ext}".
+	Path string
+	// The segment of the path that can't be translated.
+	Segment string
+	// Why the segment can't be translated.
+	Reason string
+}
+
+func (e *PathTemplateError) Error() string {
+	return fmt.Sprintf("unable to translate the path of %s into a HttpRule template: segment '%s' %s",
+		operationKey(e.Method, e.Path), e.Segment, e.Reason)
+}
+
+// translatePathTemplate translates the OpenAPI path of 'method' into a HttpRule path template. Path variables are
+// renamed to the fields of the request message that hold the path parameters (e.g. "{bookId}" becomes "{book_id}")
+// and reserved expansions match multiple segments (e.g. "{+path}" becomes "{path=**}"). A PathTemplateError is
+// returned for segments that can't be expressed as a template, e.g. "{name}.{ext}". Variables without a field in the
+// request message (e.g. parameters of the path item, which aren't part of the surface model) keep their name and are
+// returned as unbound.
+func translatePathTemplate(method *surface_v1.Method, types []*surface_v1.Type) (string, []string, error) {
+	newError := func(segment string, reason string) error {
+		return &PathTemplateError{Method: method.Method, Path: method.Path, Segment: segment, Reason: reason}
+	}
+
+	unbound := make([]string, 0)
+	segments := strings.Split(strings.TrimPrefix(method.Path, "/"), "/")
+	for i, segment := range segments {
+		isLast := i == len(segments)-1
+
+		// Only the last segment may have a verb, e.g.: "/books/{book}:publish".
+		verb := ""
+		if idx := strings.LastIndex(segment, ":"); isLast && idx > strings.LastIndex(segment, "}") {
+			segment, verb = segment[:idx], segment[idx:]
+			if !isLiteralSegment(verb[1:]) {
+				return "", nil, newError(segments[i], "has an invalid verb")
+			}
+		}
+
+		switch {
+		case segment == "" && verb == "":
+			// The root path or a trailing slash.
+		case isLiteralSegment(segment):
+			segments[i] = segment + verb
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && strings.Count(segment, "{") == 1:
+			name := segment[1 : len(segment)-1]
+			multiSegment := strings.HasPrefix(name, "+")
+			name = strings.TrimPrefix(name, "+")
+			fieldName := findPathFieldName(method.ParametersTypeName, name, types)
+			if fieldName == "" {
+				unbound = append(unbound, name)
+				fieldName = name
+			}
+			if multiSegment && !isLast {
+				return "", nil, newError(segments[i], "matches multiple segments but isn't the last segment")
+			}
+			if multiSegment {
+				segments[i] = "{" + fieldName + "=**}" + verb
+			} else {
+				segments[i] = "{" + fieldName + "}" + verb
+			}
+		default:
+			return "", nil, newError(segments[i], "must be either a literal or a single variable")
+		}
+	}
+	return "/" + strings.Join(segments, "/"), unbound, nil
+}
+
+// isLiteralSegment returns true if 'segment' is a valid literal inside a path template.
+func isLiteralSegment(segment string) bool {
+	return segment != "" && !strings.ContainsAny(segment, "{}*:=")
+}
+
+// findPathFieldName returns the name of the field of the request message 'requestTypeName' that holds the path
+// parameter 'name' or an empty string if there is no such field.
+func findPathFieldName(requestTypeName string, name string, types []*surface_v1.Type) string {
+	for _, t := range types {
+		if t.TypeName != requestTypeName {
+			continue
+		}
+		for _, f := range t.Fields {
+			if f.Position == surface_v1.Position_PATH && (f.Name == name || f.FieldName == protoFieldName(name, "")) {
+				return f.FieldName
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	"github.com/google/gnostic-grpc/utils"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// findParameters returns the parameters of 'operation' which are located in one of 'locations' (e.g. "query"),
+// keyed by the name of their request field. References to the parameters of the components of 'document' are
+// resolved.
+func findParameters(document *openapiv3.Document, operation *openapiv3.Operation, locations ...string) map[string]*openapiv3.Parameter {
+	parameters := make(map[string]*openapiv3.Parameter)
+	for _, parameterOrReference := range operation.GetParameters() {
+		parameter, name := utils.ResolveParameter(document, parameterOrReference)
+		if parameter != nil && utils.Contains(locations, parameter.In) {
+			parameters[name] = parameter
+		}
+	}
+	return parameters
+}
+
+// flattenQueryObjects replaces the fields of exploded form object parameters (e.g. "?size=10&token=abc" for the
+// object parameter "page") by the fields of their message, because the properties are sent as top-level query
+// parameters. Messages of inline schemas that aren't used anymore are removed. deepObject parameters remain nested
+// message fields, which are populated from dotted query parameters (e.g. "?filter.author=x").
+func flattenQueryObjects(model *surface_v1.Model, document *openapiv3.Document) {
+	flattened := make([]string, 0)
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		operation := findOperation(document, m.Method, m.Path)
+		if request == nil || operation == nil {
+			continue
+		}
+		parameters := findParameters(document, operation, "query")
+		fields := make([]*surface_v1.Field, 0)
+		for _, f := range request.Fields {
+			parameter, ok := parameters[f.Name]
+			object := model.TypeWithTypeName(f.NativeType)
+			if !ok || object == nil || f.Position != surface_v1.Position_QUERY || !utils.IsFlattenedQueryObject(document, operation, parameter) {
+				fields = append(fields, f)
+				continue
+			}
+			for _, property := range object.Fields {
+				property = copyField(property)
+				property.Position = surface_v1.Position_QUERY
+				fields = append(fields, property)
+			}
+			flattened = append(flattened, object.TypeName)
+		}
+		request.Fields = fields
+	}
+
+	components := make([]string, 0)
+	for _, named := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		components = append(components, named.Name)
+	}
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if !utils.Contains(flattened, t.TypeName) || utils.Contains(components, t.Name) || isUsedAsField(model, t.TypeName) {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+}
+
+// buildQueryComment documents how the query string of 'operation' maps onto the fields of the request message if
+// it has object parameters or parameters whose style gRPC HTTP/JSON transcoding doesn't understand, e.g.:
+//
+//	Query parameters:
+//	  - filter[author]: send as filter.author
+//	  - page (form, exploded): the fields size, token are top-level query parameters
+//	  - fields (pipeDelimited): not supported, repeat the parameter instead (fields=a&fields=b)
+//
+// An empty string is returned if the query string maps onto the request fields one to one.
+func buildQueryComment(document *openapiv3.Document, operation *openapiv3.Operation) string {
+	lines := []string{"Query parameters:"}
+	for _, parameterOrReference := range operation.GetParameters() {
+		parameter, _ := utils.ResolveParameter(document, parameterOrReference)
+		if parameter.GetIn() != "query" {
+			continue
+		}
+		name := parameter.Name
+		switch parameter.Style {
+		case "deepObject":
+			schema := utils.ResolveSchema(document, parameter.GetSchema())
+			for _, property := range schema.GetProperties().GetAdditionalProperties() {
+				lines = append(lines, "  - "+name+"["+property.Name+"]: send as "+name+"."+property.Name)
+			}
+		case "spaceDelimited", "pipeDelimited":
+			lines = append(lines, "  - "+name+" ("+parameter.Style+"): not supported, repeat the parameter instead ("+
+				name+"=a&"+name+"=b)")
+		default:
+			if !utils.IsFlattenedQueryObject(document, operation, parameter) {
+				continue
+			}
+			properties := make([]string, 0)
+			for _, property := range utils.ResolveSchema(document, parameter.GetSchema()).GetProperties().GetAdditionalProperties() {
+				properties = append(properties, property.Name)
+			}
+			lines = append(lines, "  - "+name+" (form, exploded): the fields "+strings.Join(properties, ", ")+
+				" are top-level query parameters")
+		}
+	}
+	if len(lines) == 1 {
+		return ""
+	}
+	return strings.Join(lines, "\n")
+}
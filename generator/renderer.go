@@ -17,20 +17,89 @@ package generator
 import (
 	"github.com/golang/protobuf/proto"
 	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/transcoding"
+	openapiv3 "github.com/google/gnostic/openapiv3"
 	plugins "github.com/google/gnostic/plugins"
 	surface "github.com/google/gnostic/surface"
 	prDesc "github.com/jhump/protoreflect/desc"
 	prPrint "github.com/jhump/protoreflect/desc/protoprint"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/genproto/googleapis/longrunning"
 )
 
 // Renderer generates a .proto file based on the information inside Model.
 type Renderer struct {
 	// The model holds the necessary information from the OpenAPI description.
 	Model *surface.Model
+	// The path of the OpenAPI document, which is used to report the lines of errors. May be empty.
+	SourceName string
 	// The FileDescriptorSet that will be printed with protoreflect
 	FdSet          *dpb.FileDescriptorSet
 	SymbolicFdSets []*dpb.FileDescriptorSet
 	Package        string // package name
+	// NamingStrategy determines the name of the generated service.
+	NamingStrategy NamingStrategy
+	// The OpenAPI document the model has been built from. It is used to read specification extensions and may be nil.
+	Document *openapiv3.Document
+	// TypeMappings maps schemas onto existing proto types, which are imported instead of generated. May be nil.
+	TypeMappings *TypeMappings
+	// ServicesPerTag groups the methods into one service per tag of the OpenAPI document instead of a single service.
+	ServicesPerTag bool
+	// WholeMessageBodies maps request bodies onto the whole request message ('body: "*"') where possible.
+	WholeMessageBodies bool
+	// OneofResponses generates a oneof over the successful responses of an operation instead of returning the response
+	// with the lowest status code.
+	OneofResponses bool
+	// MetadataParameters passes header and cookie parameters as gRPC metadata instead of request fields.
+	MetadataParameters bool
+	// InferResources annotates resources, resource references and method signatures that are inferred from the paths.
+	InferResources bool
+	// DetectPagination reports the methods that follow a common pagination pattern.
+	DetectPagination bool
+	// PaginationFields renames the pagination fields of the detected methods according to AIP-158.
+	PaginationFields bool
+	// UpdateMasks adds a google.protobuf.FieldMask update_mask to the requests of PATCH methods.
+	UpdateMasks bool
+	// FileOptions sets the language-specific options of the generated file. May be nil.
+	FileOptions *FileOptions
+	// LongRunningOperations makes operations that answer with 202 Accepted return a google.longrunning.Operation.
+	LongRunningOperations bool
+	// PrefixBasePath prefixes the path of the first server of the document (e.g. "/v1") to the HttpRule templates.
+	PrefixBasePath bool
+	// StripBasePath strips the path of the first server of the document from the HttpRule templates that start with it.
+	StripBasePath bool
+	// ErrorComments documents the error responses of the operations in the comments of their RPCs.
+	ErrorComments bool
+	// ServiceConfig renders a google.api.Service configuration in this format ("yaml" or "json"). May be empty.
+	ServiceConfig string
+	// MergeIdenticalSignatures merges operations with identical signatures under paths that only differ by a prefix
+	// into one RPC, in addition to operations with the same operationId or x-grpc-method.
+	MergeIdenticalSignatures bool
+
+	// The additional_bindings of the methods that are shared by several operations.
+	additionalBindings map[*surface.Method][]*annotations.HttpRule
+	// The operations that have been merged into another RPC because of their identical signatures.
+	mergedSignatures []*plugins.Message
+	// The path variables without a field in the request message, which are reported to the user.
+	unboundPathVariables []*plugins.Message
+	// The methods whose request body is the whole request message.
+	wholeMessageBodies map[*surface.Method]bool
+	// The error responses of the methods, which are documented in the comments of the RPCs.
+	errorResponses map[*surface.Method][]*errorResponse
+	// The types that hold a oneof over the successful responses of a method and the name of that oneof.
+	oneofTypes map[*surface.Type]string
+	// The header and cookie parameters of the methods, which are passed as gRPC metadata.
+	metadataParameters map[*surface.Method][]*transcoding.Metadata
+	// The AIP annotations that have been inferred if InferResources is set.
+	resources *resourceAnnotations
+	// The paginated methods that have been detected if DetectPagination is set.
+	pagination *paginationAnnotations
+	// The operation_info options of the methods that return a google.longrunning.Operation.
+	operationInfos map[*surface.Method]*longrunning.OperationInfo
+	// The operations that have been renamed because of duplicate symbols, which is reported to the user.
+	renamedOperations []*plugins.Message
+	// The methods of the RPCs by their fully qualified names, e.g.: "bookstore.Bookstore.GetBook".
+	rpcMethods map[string]*surface.Method
 }
 
 // NewRenderer creates a renderer.
@@ -38,6 +107,7 @@ func NewRenderer(model *surface.Model) (renderer *Renderer) {
 	renderer = &Renderer{}
 	renderer.Model = model
 	renderer.SymbolicFdSets = make([]*dpb.FileDescriptorSet, 0)
+	renderer.NamingStrategy = &DefaultNamingStrategy{}
 	return renderer
 }
 
@@ -63,6 +133,21 @@ func (renderer *Renderer) Render(response *plugins.Response, fileName string) (e
 		return err
 	}
 	response.Files = append(response.Files, f)
+	if renderer.ServiceConfig != "" {
+		configFile, messages, err := renderer.RenderServiceConfig(fileName, renderer.ServiceConfig)
+		if err != nil {
+			return err
+		}
+		response.Files = append(response.Files, configFile)
+		response.Messages = append(response.Messages, messages...)
+	}
+	if renderer.resources != nil {
+		response.Messages = append(response.Messages, renderer.resources.summary...)
+	}
+	response.Messages = append(response.Messages, renderer.pagination.summary()...)
+	response.Messages = append(response.Messages, renderer.renamedOperations...)
+	response.Messages = append(response.Messages, renderer.mergedSignatures...)
+	response.Messages = append(response.Messages, renderer.unboundPathVariables...)
 
 	// Render external proto definitions.
 	for _, externalSet := range renderer.SymbolicFdSets {
@@ -15,6 +15,7 @@
 package generator
 
 import (
+	"errors"
 	"io/ioutil"
 	"os"
 	"path"
@@ -22,6 +23,8 @@ import (
 	"strings"
 	"testing"
 
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
 	surface "github.com/google/gnostic/surface"
 
 	"github.com/google/gnostic-grpc/utils"
@@ -47,6 +50,17 @@ func TestFileDescriptorGeneratorParameters(t *testing.T) {
 	checkContents(t, string(protoData), "goldstandard/parameters.proto")
 }
 
+func TestFileDescriptorGeneratorPetstore(t *testing.T) {
+	// The request body and the schema "Pet" of the components are different types of the same name.
+	input := "../examples/petstore/petstore.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "petstore")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/petstore.proto")
+}
+
 func TestFileDescriptorGeneratorRequestBodies(t *testing.T) {
 	input := "testfiles/requestBodies.yaml"
 
@@ -69,6 +83,408 @@ func TestFileDescriptorGeneratorResponses(t *testing.T) {
 	checkContents(t, string(protoData), "goldstandard/responses.proto")
 }
 
+func TestFileDescriptorGeneratorResponseBodies(t *testing.T) {
+	input := "testfiles/responsebodies.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "responsebodies")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/responsebodies.proto")
+}
+
+func TestFileDescriptorGeneratorWholeMessageBodies(t *testing.T) {
+	input := "testfiles/wholemessagebodies.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "wholemessagebodies", map[string]string{"request_body": "whole_message"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/wholemessagebodies.proto")
+}
+
+func TestFileDescriptorGeneratorStreaming(t *testing.T) {
+	input := "testfiles/streaming.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "streaming")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/streaming.proto")
+}
+
+func TestFileDescriptorGeneratorHttpBody(t *testing.T) {
+	input := "testfiles/httpbody.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "httpbody")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/httpbody.proto")
+}
+
+func TestFileDescriptorGeneratorErrorResponses(t *testing.T) {
+	input := "testfiles/errorresponses.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "errorresponses", map[string]string{"errors": "comment"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/errorresponses.proto")
+
+	// By default the error responses aren't documented.
+	protoData, err = runGeneratorWithParameters(input, "errorresponses", nil)
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	if strings.Contains(string(protoData), "Error responses") {
+		t.Errorf("Expected no comments of error responses, got:\n%s", protoData)
+	}
+}
+
+func TestFileDescriptorGeneratorOneofResponses(t *testing.T) {
+	input := "testfiles/oneofresponses.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "oneofresponses", map[string]string{"responses": "oneof"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/oneofresponses.proto")
+
+	_, err = runGeneratorWithParameters(input, "oneofresponses", map[string]string{"responses": "all"})
+	if err == nil {
+		t.Errorf("Expected an error for an unsupported value of the parameter responses")
+	}
+}
+
+func TestFileDescriptorGeneratorMetadata(t *testing.T) {
+	input := "testfiles/metadata.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "metadata", map[string]string{"headers": "metadata"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/metadata.proto")
+}
+
+func TestFileDescriptorGeneratorQueryObjects(t *testing.T) {
+	input := "testfiles/queryobjects.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "queryobjects")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/queryobjects.proto")
+}
+
+func TestFileDescriptorGeneratorResources(t *testing.T) {
+	input := "testfiles/resources.yaml"
+
+	response, err := renderWithParameters(input, "resources", map[string]string{"resources": "infer"})
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	checkContents(t, string(response.Files[0].Data), "goldstandard/resources.proto")
+
+	// The inferred annotations are summarized in the messages of the response.
+	expectedTexts := []string{
+		`Inferred the resource library.example.com/Shelf with the pattern "shelves/{shelf}" for the message Shelf.`,
+		`Inferred the resource library.example.com/Book with the pattern "shelves/{shelf}/books/{book_id}" for the message Book.`,
+		`The message Author has no string field name that holds resource names of the pattern "authors/{author}", so it isn't annotated as a resource.`,
+		`The message Publisher has no string field name that holds resource names of the pattern "publishers/{publisher}", so it isn't annotated as a resource.`,
+		`Inferred the resource reference library.example.com/Shelf for the field shelf_name of Book.`,
+		`Inferred the method signature "shelf" for GetShelf. The path fields shelf (library.example.com/Shelf) hold resource IDs rather than resource names, so they get no google.api.resource_reference.`,
+		`Inferred the method signature "shelf" for ListBooks. The path fields shelf (library.example.com/Shelf) hold resource IDs rather than resource names, so they get no google.api.resource_reference.`,
+		`Inferred the method signature "shelf,book_id" for GetBook. The path fields shelf (library.example.com/Shelf), book_id (library.example.com/Book) hold resource IDs rather than resource names, so they get no google.api.resource_reference.`,
+		`Inferred the method signature "shelf,book_id" for ArchiveBook. The path fields shelf (library.example.com/Shelf), book_id (library.example.com/Book) hold resource IDs rather than resource names, so they get no google.api.resource_reference.`,
+		`Inferred the method signature "author" for GetAuthor.`,
+		`Inferred the method signature "publisher" for GetPublisher.`,
+	}
+	if len(response.Messages) != len(expectedTexts) {
+		t.Fatalf("Expected %d messages, got %d", len(expectedTexts), len(response.Messages))
+	}
+	for i, message := range response.Messages {
+		if message.Text != expectedTexts[i] {
+			t.Errorf("Expected the message %q, got %q", expectedTexts[i], message.Text)
+		}
+	}
+}
+
+func TestFileDescriptorGeneratorPagination(t *testing.T) {
+	input := "testfiles/pagination.yaml"
+
+	response, err := renderWithParameters(input, "pagination", map[string]string{"pagination": "aip"})
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	checkContents(t, string(response.Files[0].Data), "goldstandard/pagination.proto")
+
+	// The paginated methods are reported in the messages of the response.
+	expectedTexts := []string{
+		`ListBooks is paginated by cursor: page size "limit", page token "cursor", items "books", next page token "nextCursor".`,
+		`ListAuthors is paginated by offset: page size "limit", page token "offset", items "items".`,
+		`ListShelves is paginated by page: page size "per_page", page token "page", items "items".`,
+	}
+	if len(response.Messages) != len(expectedTexts) {
+		t.Fatalf("Expected %d messages, got %d", len(expectedTexts), len(response.Messages))
+	}
+	for i, message := range response.Messages {
+		if message.Text != expectedTexts[i] {
+			t.Errorf("Expected the message %q, got %q", expectedTexts[i], message.Text)
+		}
+	}
+}
+
+func TestFileDescriptorGeneratorUpdateMask(t *testing.T) {
+	input := "testfiles/updatemask.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "updatemask", map[string]string{"patch": "update_mask"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/updatemask.proto")
+}
+
+func TestFileDescriptorGeneratorLongRunning(t *testing.T) {
+	input := "testfiles/longrunning.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "longrunning", map[string]string{"accepted": "operation"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/longrunning.proto")
+}
+
+func TestFileDescriptorGeneratorOperationIds(t *testing.T) {
+	input := "testfiles/operationids.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "operationids", map[string]string{})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/operationids.proto")
+}
+
+func TestFileDescriptorGeneratorDuplicates(t *testing.T) {
+	input := "testfiles/duplicates.yaml"
+
+	_, err := runGeneratorWithParameters(input, "duplicates", map[string]string{})
+	if err == nil {
+		t.Fatalf("Expected an error for the duplicate symbols of %s", input)
+	}
+	for _, expected := range []string{
+		"getBook is generated for #/paths/~1books/get/operationId (testfiles/duplicates.yaml:11:7) and " +
+			"#/paths/~1magazines/get/operationId (testfiles/duplicates.yaml:26:7)",
+		"GetAuthorRequest is generated for #/components/schemas/GetAuthorRequest (testfiles/duplicates.yaml:81:5) and " +
+			"#/paths/~1authors/get/operationId (testfiles/duplicates.yaml:42:7)",
+		"Ping is generated for #/paths/~1ping/get/operationId (testfiles/duplicates.yaml:57:7) and " +
+			"#/paths/~1status/get/operationId (testfiles/duplicates.yaml:63:7)",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Error does not report the duplicate symbol %q: %s", expected, err.Error())
+		}
+	}
+
+	protoData, err := runGeneratorWithParameters(input, "duplicates", map[string]string{"duplicates": "rename"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/duplicates.proto")
+}
+
+func TestFileDescriptorGeneratorFileOptions(t *testing.T) {
+	input := "testfiles/fileoptions.yaml"
+	parameters := map[string]string{
+		"file_options": "testfiles/fileoptions-config.yaml",
+		"ruby_package": "Acme::Bookstore::V1",
+	}
+
+	protoData, err := runGeneratorWithParameters(input, "fileoptions", parameters)
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/fileoptions.proto")
+
+	// The path of the output file follows the package.
+	if path := protoFilePath("acme.bookstore.v1"); path != "acme/bookstore/v1/bookstore.proto" {
+		t.Errorf("Expected the path acme/bookstore/v1/bookstore.proto, got %s", path)
+	}
+	if path := protoFilePath("bookstore"); path != "bookstore.proto" {
+		t.Errorf("Expected the path bookstore.proto, got %s", path)
+	}
+
+	// A later file resets java_multiple_files.
+	generatorParameters, err := parseParameters([]*plugins.Parameter{
+		{Name: "file_options", Value: "testfiles/fileoptions-config.yaml"},
+		{Name: "file_options", Value: "testfiles/fileoptions-override-config.yaml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multipleFiles := generatorParameters.fileOptions.JavaMultipleFiles; multipleFiles == nil || *multipleFiles {
+		t.Errorf("Expected java_multiple_files to be reset to false")
+	}
+	if generatorParameters.fileOptions.JavaPackage != "com.acme.bookstore.v1" {
+		t.Errorf("Expected the java_package of the first file, got %s", generatorParameters.fileOptions.JavaPackage)
+	}
+
+	// Misspelled keys are rejected.
+	if _, err := LoadFileOptions("testfiles/errors/fileoptions-unknown-config.yaml"); err == nil {
+		t.Errorf("Expected an error for the unknown key java_multiple_file")
+	}
+}
+
+func TestFileDescriptorGeneratorServers(t *testing.T) {
+	input := "testfiles/servers.yaml"
+	parameters := map[string]string{
+		"version":   "server",
+		"base_path": "prefix",
+	}
+
+	protoData, err := runGeneratorWithParameters(input, "servers", parameters)
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/servers.proto")
+
+	document := &openapiv3.Document{Info: &openapiv3.Info{}}
+	for _, test := range []struct {
+		infoVersion string
+		version     string
+	}{
+		{"1", "v1"},
+		{"1.2.0", "v1"},
+		{"v2beta1", "v2beta1"},
+		{"2.0.0-beta.1", "v2beta1"},
+		{"1.0.0-alpha", "v1alpha"},
+		{"3.0.0-rc.1", "v3"},
+		{"latest", ""},
+	} {
+		document.Info.Version = test.infoVersion
+		if version, _ := apiVersion(document, versionFromInfo); version != test.version {
+			t.Errorf("Expected the version %q for info.version %q, got %q", test.version, test.infoVersion, version)
+		}
+	}
+
+	document.Servers = []*openapiv3.Server{{Url: "https://bookstore.example.com/api/v1/"}}
+	renderer := &Renderer{Document: document, StripBasePath: true}
+	for path, expected := range map[string]string{
+		"/api/v1/books/{book}": "/books/{book}",
+		"/api/v1":              "/",
+		"/api/v10/books":       "/api/v10/books",
+		"/books":               "/books",
+	} {
+		if stripped := renderer.applyBasePath(path); stripped != expected {
+			t.Errorf("Expected the template %s for %s, got %s", expected, path, stripped)
+		}
+	}
+}
+
+func TestFileDescriptorGeneratorServiceConfig(t *testing.T) {
+	input := "testfiles/serviceconfig.yaml"
+	for _, format := range []string{"yaml", "json"} {
+		response, err := renderWithParameters(input, "serviceconfig", map[string]string{"service_config": format})
+		if err != nil {
+			handleError(err, t)
+			return
+		}
+		if len(response.Files) != 2 || response.Files[1].Name != "serviceconfig_service."+format {
+			t.Errorf("Expected the files serviceconfig.proto and serviceconfig_service.%s", format)
+			return
+		}
+		checkContents(t, string(response.Files[1].Data), "goldstandard/serviceconfig_service."+format)
+
+		expectedMessageKeys := [][]string{{"components", "securitySchemes", "api_key"}}
+		validateKeys(t, expectedMessageKeys, response.Messages)
+	}
+}
+
+func TestFileDescriptorGeneratorForms(t *testing.T) {
+	input := "testfiles/forms.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "forms")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/forms.proto")
+}
+
+func TestFileDescriptorGeneratorCustomMethods(t *testing.T) {
+	input := "testfiles/custommethods.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "custommethods")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/custommethods.proto")
+}
+
+func TestFileDescriptorGeneratorAdditionalBindings(t *testing.T) {
+	input := "testfiles/bindings.yaml"
+
+	response, err := renderWithParameters(input, "bindings", map[string]string{"bindings": "signatures"})
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	checkContents(t, string(response.Files[0].Data), "goldstandard/bindings.proto")
+
+	// Each merge of identical signatures is reported.
+	expectedText := "The operation GET /books/{book} has the same signature as GET /v1/books/{book}, it is generated " +
+		"as an additional binding of its RPC."
+	if len(response.Messages) != 1 || response.Messages[0].Text != expectedText {
+		t.Errorf("Expected the message %q, got %v", expectedText, response.Messages)
+	}
+
+	// Without bindings=signatures only operationIds and x-grpc-method share RPCs.
+	response, err = renderWithParameters(input, "bindings", nil)
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	if protoData := string(response.Files[0].Data); !strings.Contains(protoData, "rpc GetBook (") ||
+		!strings.Contains(protoData, "rpc GetBookV1 (") {
+		t.Errorf("Expected separate RPCs GetBook and GetBookV1, got:\n%s", protoData)
+	}
+	if len(response.Messages) != 0 {
+		t.Errorf("Expected no messages, got %v", response.Messages)
+	}
+}
+
+func TestFileDescriptorGeneratorPathTemplates(t *testing.T) {
+	input := "testfiles/pathtemplates.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "pathtemplates")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/pathtemplates.proto")
+
+	_, err = runGeneratorWithoutPluginEnvironment("testfiles/errors/partial_segment.yaml", "partial_segment")
+	var pathTemplateError *PathTemplateError
+	if !errors.As(err, &pathTemplateError) || pathTemplateError.Segment != "{name}.{ext}" {
+		t.Errorf("Expected a PathTemplateError for the segment {name}.{ext}, got: %v", err)
+	}
+
+	// Parameters of the path item aren't part of the surface model, their variables keep their names.
+	response, err := renderWithParameters("testfiles/pathitemparameters.yaml", "pathitemparameters", nil)
+	if err != nil {
+		handleError(err, t)
+		return
+	}
+	if protoData := string(response.Files[0].Data); !strings.Contains(protoData, `get:"/books/{book}"`) {
+		t.Errorf("Expected the path template /books/{book}, got:\n%s", protoData)
+	}
+	expectedText := "The path variable book of GET /books/{book} has no field in the request message, the path " +
+		"template keeps its name."
+	if len(response.Messages) != 1 || response.Messages[0].Text != expectedText {
+		t.Errorf("Expected the message %q, got %v", expectedText, response.Messages)
+	}
+}
+
 func TestFileDescriptorGeneratorOther(t *testing.T) {
 	input := "testfiles/other.yaml"
 
@@ -92,34 +508,178 @@ func TestFileDescriptorGeneratorOther(t *testing.T) {
 	}
 }
 
+func TestFileDescriptorGeneratorNamingStrategy(t *testing.T) {
+	input := "testfiles/naming.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "naming", map[string]string{"naming": "aip"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/naming.proto")
+
+	_, err = runGeneratorWithParameters(input, "naming", map[string]string{"naming": "unknown"})
+	if err == nil {
+		t.Errorf("Expected an error for an unsupported naming strategy")
+	}
+}
+
+func TestFileDescriptorGeneratorExtensions(t *testing.T) {
+	input := "testfiles/extensions.yaml"
+
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "extensions")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/extensions.proto")
+}
+
+func TestFileDescriptorGeneratorFieldNumbers(t *testing.T) {
+	_, err := runGeneratorWithoutPluginEnvironment("testfiles/errors/reserved_field_number.yaml", "reserved_field_number")
+	expectedError := "field number 19000 of field isbn in message Book is reserved by protobuf (19000-19999)"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected the error %q, got: %v", expectedError, err)
+	}
+
+	// Numbers above the maximum field number aren't truncated to valid ones.
+	book := &surface.Type{Name: "Book", TypeName: "Book", Fields: []*surface.Field{{Name: "isbn", FieldName: "isbn"}}}
+	extensions := &protoExtensions{properties: map[string]map[string]*protoOverrides{
+		"Book": {"isbn": {fieldNumber: 1 << 32}},
+	}}
+	_, err = fieldNumbers(book, book.Fields, extensions)
+	expectedError = "field number 4294967296 of field isbn in message Book is larger than 536870911"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected the error %q, got: %v", expectedError, err)
+	}
+}
+
+func TestFileDescriptorGeneratorMessagesOfOtherFiles(t *testing.T) {
+	input := "testfiles/extensions.yaml"
+
+	// Renders the messages of another package first, which include a message Shelf as well.
+	documentv3, surfaceModel, err := buildSurfaceModel(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewProtoLanguageModel().Prepare(surfaceModel, "openapi.v3.Document")
+	renderer := NewRenderer(surfaceModel)
+	renderer.Document = documentv3
+	renderer.Package = "acme.library"
+	if _, err := renderer.runFileDescriptorSetGenerator(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The field shelf of Volume, which precedes Shelf, references the Shelf of its own package.
+	protoData, err := runGeneratorWithoutPluginEnvironment(input, "extensions")
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/extensions.proto")
+}
+
+func TestFileDescriptorGeneratorTypeMappings(t *testing.T) {
+	input := "testfiles/typemapping.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "typemapping",
+		map[string]string{"type_mappings": "testfiles/typemapping-config.yaml"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/typemapping.proto")
+}
+
+func TestFileDescriptorGeneratorTypeMappingRefs(t *testing.T) {
+	input := "testfiles/typemappingrefs.yaml"
+	config := "testfiles/typemappingrefs-config.yaml"
+
+	// Only the fields that reference the mapped $ref URL become google.type.Money, the local schema Money is kept.
+	protoData, err := runGeneratorWithParameters(input, "typemappingrefs", map[string]string{"type_mappings": config})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/typemappingrefs.proto")
+
+	document, err := utils.ParseOpenAPIDoc(input)
+	if err != nil {
+		t.Errorf("Error while parsing input file: %s", input)
+		return
+	}
+	typeMappings, err := LoadTypeMappings(config)
+	if err != nil {
+		t.Errorf("Error while loading the type mappings: %s", err)
+		return
+	}
+	if !typeMappings.coversSymbolicReference(document, "typemapping-common.yaml") {
+		t.Errorf("Expected typemapping-common.yaml to be covered by the type mappings")
+	}
+	if typeMappings.coversSymbolicReference(document, "other.yaml") {
+		t.Errorf("Expected other.yaml not to be covered, it isn't referenced")
+	}
+	schemaMappings, err := LoadTypeMappings("testfiles/typemapping-config.yaml")
+	if err != nil {
+		t.Errorf("Error while loading the type mappings: %s", err)
+		return
+	}
+	if schemaMappings.coversSymbolicReference(document, "typemapping-common.yaml") {
+		t.Errorf("Expected typemapping-common.yaml not to be covered by mappings of schema names")
+	}
+}
+
+func TestFileDescriptorGeneratorServicesPerTag(t *testing.T) {
+	input := "testfiles/tags.yaml"
+
+	protoData, err := runGeneratorWithParameters(input, "tags", map[string]string{"services": "tags"})
+	if err != nil {
+		handleError(err, t)
+	}
+	checkContents(t, string(protoData), "goldstandard/tags.proto")
+}
+
 func runGeneratorWithoutPluginEnvironment(input string, packageName string) ([]byte, error) {
-	surfaceModel, err := buildSurfaceModel(input)
+	return runGeneratorWithParameters(input, packageName, nil)
+}
+
+// runGeneratorWithParameters runs the generator as if gnostic invoked it with the plugin parameters 'parameters' and
+// returns the .proto file.
+func runGeneratorWithParameters(input string, packageName string, parameters map[string]string) ([]byte, error) {
+	response, err := renderWithParameters(input, packageName, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return response.Files[0].Data, nil
+}
+
+// renderWithParameters renders 'input' with the plugin parameters 'parameters' like the plugin does and returns the
+// response.
+func renderWithParameters(input string, packageName string, parameters map[string]string) (*plugins.Response, error) {
+	pluginParameters := make([]*plugins.Parameter, 0)
+	for name, value := range parameters {
+		pluginParameters = append(pluginParameters, &plugins.Parameter{Name: name, Value: value})
+	}
+	generatorParameters, err := parseParameters(pluginParameters)
 	if err != nil {
 		return nil, err
 	}
-	NewProtoLanguageModel().Prepare(surfaceModel, "openapi.v3.Document")
-	r := NewRenderer(surfaceModel)
-	r.Package = packageName
 
-	fdSet, err := r.runFileDescriptorSetGenerator()
-	r.FdSet = fdSet
+	documentv3, surfaceModel, err := buildSurfaceModel(input)
 	if err != nil {
 		return nil, err
 	}
-	f, err := r.RenderProto(fdSet, "")
+	r, err := newRendererForParameters(surfaceModel, documentv3, input, "openapi.v3.Document", packageName, generatorParameters)
 	if err != nil {
 		return nil, err
 	}
-	return f.Data, err
+	response := &plugins.Response{}
+	err = r.Render(response, protoFilePath(r.Package))
+	return response, err
 }
 
-func buildSurfaceModel(input string) (*surface.Model, error) {
+func buildSurfaceModel(input string) (*openapiv3.Document, *surface.Model, error) {
 	documentv3, err := utils.ParseOpenAPIDoc(input)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	surfaceModel, err := surface.NewModelFromOpenAPI3(documentv3, input)
-	return surfaceModel, err
+	return documentv3, surfaceModel, err
 }
 
 func writeFile(output string, protoData []byte) {
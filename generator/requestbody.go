@@ -0,0 +1,116 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	surface_v1 "github.com/google/gnostic/surface"
+)
+
+// inlineRequestBodies maps the request bodies of the methods of the model onto the whole request message, i.e. the
+// HttpRule of those methods gets 'body: "*"'. A request body is mapped if it references a message and
+//   - there are no other parameters: the referenced message becomes the input of the RPC, or
+//   - there are path parameters only: the fields of the referenced message are added to the request message.
+//
+// Other request bodies (e.g. with query parameters, which can't be combined with 'body: "*"') are left untouched.
+func inlineRequestBodies(renderer *Renderer) {
+	renderer.wholeMessageBodies = make(map[*surface_v1.Method]bool)
+	if !renderer.WholeMessageBodies {
+		return
+	}
+	model := renderer.Model
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		if request == nil {
+			continue
+		}
+		var body *surface_v1.Field
+		pathParameters := make([]*surface_v1.Field, 0)
+		for _, f := range request.Fields {
+			switch f.Position {
+			case surface_v1.Position_BODY:
+				body = f
+			case surface_v1.Position_PATH:
+				pathParameters = append(pathParameters, f)
+			}
+		}
+		if body == nil || len(pathParameters)+1 != len(request.Fields) || body.Kind != surface_v1.FieldKind_REFERENCE {
+			continue
+		}
+		bodyType := model.TypeWithTypeName(body.NativeType)
+		if bodyType == nil {
+			continue
+		}
+
+		if len(pathParameters) == 0 {
+			m.ParametersTypeName = bodyType.TypeName
+		} else if !hasFieldNameCollision(pathParameters, bodyType.Fields) {
+			fields := pathParameters
+			for _, f := range bodyType.Fields {
+				fields = append(fields, proto.Clone(f).(*surface_v1.Field))
+			}
+			request.Fields = fields
+		} else {
+			continue
+		}
+		renderer.wholeMessageBodies[m] = true
+		removeUnusedTypes(model, request, bodyType)
+	}
+}
+
+// hasFieldNameCollision returns true if one of 'fields' has the same name as one of 'others'.
+func hasFieldNameCollision(fields []*surface_v1.Field, others []*surface_v1.Field) bool {
+	for _, f := range fields {
+		for _, other := range others {
+			if f.FieldName == other.FieldName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeUnusedTypes removes the 'candidates' from the model that are neither used by a method nor by a field. Only
+// types that have been generated for an operation (and not for a component schema) are removed.
+func removeUnusedTypes(model *surface_v1.Model, candidates ...*surface_v1.Type) {
+	used := make(map[string]bool)
+	for _, m := range model.Methods {
+		used[m.ParametersTypeName] = true
+		used[m.ResponsesTypeName] = true
+	}
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			used[f.NativeType] = true
+		}
+	}
+	operationTypes := make(map[*surface_v1.Type]bool)
+	for _, candidate := range candidates {
+		for _, m := range model.Methods {
+			if m.Operation != "" && strings.HasPrefix(strings.ToLower(candidate.Name), strings.ToLower(m.Operation)) {
+				operationTypes[candidate] = true
+			}
+		}
+	}
+
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if !operationTypes[t] || used[t.TypeName] {
+			types = append(types, t)
+		}
+	}
+	model.Types = types
+}
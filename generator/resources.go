@@ -0,0 +1,331 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/google/gnostic-grpc/utils"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	surface_v1 "github.com/google/gnostic/surface"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"gopkg.in/yaml.v3"
+)
+
+// The files that define the (google.api.resource), (google.api.resource_reference) and (google.api.method_signature)
+// options.
+const (
+	resourceOptionFile = "google/api/resource.proto"
+	clientOptionFile   = "google/api/client.proto"
+)
+
+// resourceAnnotations holds the AIP annotations (https://google.aip.dev/123) that have been inferred from the paths of
+// the methods.
+type resourceAnnotations struct {
+	// The resources by the name of their message.
+	resources map[string]*annotations.ResourceDescriptor
+	// The resource types referenced by the fields that hold resource names, keyed by message and field name.
+	references map[string]map[string]string
+	// The method signatures of the methods.
+	signatures map[*surface_v1.Method]string
+	// What has been inferred, which is reported to the user.
+	summary []*plugins.Message
+}
+
+// inferResources infers the AIP annotations of the methods of 'renderer':
+//   - The message returned by GET on a path of collection/{variable} pairs (e.g. "/shelves/{shelf}/books/{book}") is
+//     a resource with that pattern, if its field "name" holds the resource name (see holdsResourceName).
+//   - Fields named after a resource with the suffix "_name" (e.g. "shelf_name") reference that resource.
+//   - The method signature consists of the path fields, which are always required.
+//
+// Path fields hold the ID of the resource whose pattern ends with their variable (e.g. "book" for "shelves/1/books/2")
+// rather than its resource name, so they don't get a (google.api.resource_reference) option. The summary names the
+// resources they identify instead.
+func inferResources(renderer *Renderer) *resourceAnnotations {
+	result := &resourceAnnotations{
+		resources:  make(map[string]*annotations.ResourceDescriptor),
+		references: make(map[string]map[string]string),
+		signatures: make(map[*surface_v1.Method]string),
+	}
+	model := renderer.Model
+	service := resourceServiceName(renderer)
+	components := make([]string, 0)
+	for _, named := range renderer.Document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		components = append(components, named.Name)
+	}
+
+	// The resource type by pattern.
+	patterns := make(map[string]string)
+	for _, m := range model.Methods {
+		segments, verb := resourcePath(m, model.TypeWithTypeName(m.ParametersTypeName))
+		response := model.TypeWithTypeName(m.ResponsesTypeName)
+		if m.Method != "GET" || verb != "" || !isResourcePattern(segments) || response == nil {
+			continue
+		}
+		if renderer.Document != nil && !utils.Contains(components, response.Name) {
+			// Messages of inline schemas are named after the operation rather than the resource.
+			continue
+		}
+		pattern := strings.Join(segments, "/")
+		if _, ok := patterns[pattern]; ok {
+			continue
+		}
+		if !holdsResourceName(renderer.Document, response, segments) {
+			result.summary = append(result.summary, newResourceMessage(m,
+				"The message "+response.TypeName+" has no string field name that holds resource names of the pattern \""+
+					pattern+"\", so it isn't annotated as a resource."))
+			continue
+		}
+		resource, ok := result.resources[response.TypeName]
+		if !ok {
+			resource = &annotations.ResourceDescriptor{
+				Type:     service + "/" + response.TypeName,
+				Plural:   segments[len(segments)-2],
+				Singular: strings.ToLower(response.TypeName[:1]) + response.TypeName[1:],
+			}
+			result.resources[response.TypeName] = resource
+		}
+		resource.Pattern = append(resource.Pattern, pattern)
+		patterns[pattern] = resource.Type
+		result.summary = append(result.summary, newResourceMessage(m,
+			"Inferred the resource "+resource.Type+" with the pattern \""+pattern+"\" for the message "+
+				response.TypeName+"."))
+	}
+
+	referencedTypes := make(map[string]string)
+	for _, resource := range result.resources {
+		referencedTypes[toSnakeCase(resource.Singular)+"_name"] = resource.Type
+	}
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			resourceType := referencedTypes[f.FieldName]
+			if resourceType == "" || f.Kind != surface_v1.FieldKind_SCALAR || f.NativeType != "string" ||
+				f.Position == surface_v1.Position_PATH {
+				continue
+			}
+			if result.references[t.TypeName] == nil {
+				result.references[t.TypeName] = make(map[string]string)
+			}
+			result.references[t.TypeName][f.FieldName] = resourceType
+			result.summary = append(result.summary, &plugins.Message{
+				Code:  "RESOURCES",
+				Level: plugins.Message_INFO,
+				Text: "Inferred the resource reference " + resourceType + " for the field " + f.FieldName + " of " +
+					t.TypeName + ".",
+				Keys: typeOrigin(model, renderer.Document, t).keys,
+			})
+		}
+	}
+
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		segments, _ := resourcePath(m, request)
+		fields := make([]string, 0)
+		identifiers := make([]string, 0)
+		for i, segment := range segments {
+			if !isPathVariable(segment) {
+				continue
+			}
+			field := strings.Trim(segment, "{}")
+			fields = append(fields, field)
+			resourceType, ok := patterns[strings.Join(segments[:i+1], "/")]
+			if !ok || request == nil {
+				continue
+			}
+			identifiers = append(identifiers, field+" ("+resourceType+")")
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		result.signatures[m] = strings.Join(fields, ",")
+		text := "Inferred the method signature \"" + result.signatures[m] + "\" for " + m.HandlerName + "."
+		if len(identifiers) > 0 {
+			text += " The path fields " + strings.Join(identifiers, ", ") + " hold resource IDs rather than " +
+				"resource names, so they get no google.api.resource_reference."
+		}
+		result.summary = append(result.summary, newResourceMessage(m, text))
+	}
+	return result
+}
+
+// resourceServiceName returns the service name of the resource types, which is the host of the first server of the
+// document (e.g. "library.example.com") or the package.
+func resourceServiceName(renderer *Renderer) string {
+	for _, server := range renderer.Document.GetServers() {
+		if u, err := url.Parse(server.Url); err == nil && u.Hostname() != "" {
+			return u.Hostname()
+		}
+	}
+	return renderer.Package
+}
+
+// resourcePath returns the segments of the path of 'method' and its custom verb. Variables are named after their
+// field in 'request', e.g.: ["shelves", "{shelf}", "books", "{book}"] and "archive" for
+// "/shelves/{shelfId}/books/{bookId}:archive".
+func resourcePath(method *surface_v1.Method, request *surface_v1.Type) (segments []string, verb string) {
+	path := strings.TrimPrefix(method.Path, "/")
+	if i := strings.LastIndex(path, ":"); i > strings.LastIndex(path, "/") && i > strings.LastIndex(path, "}") {
+		path, verb = path[:i], path[i+1:]
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if isPathVariable(segment) && request != nil {
+			for _, f := range request.Fields {
+				if f.Position == surface_v1.Position_PATH && "{"+f.Name+"}" == segment {
+					segment = "{" + f.FieldName + "}"
+				}
+			}
+		}
+		segments = append(segments, segment)
+	}
+	return segments, verb
+}
+
+// holdsResourceName returns true if the message 'resource' has a string field "name" that holds resource names of the
+// pattern 'segments', i.e. the examples of its schema, if any, have the collections of the pattern (e.g.
+// "shelves/1/books/2" for "shelves/{shelf}/books/{book}" but not "doggie").
+func holdsResourceName(document *openapiv3.Document, resource *surface_v1.Type, segments []string) bool {
+	var field *surface_v1.Field
+	for _, f := range resource.Fields {
+		if f.FieldName == "name" && f.Kind == surface_v1.FieldKind_SCALAR && f.NativeType == "string" {
+			field = f
+		}
+	}
+	if field == nil {
+		return false
+	}
+	for _, named := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		if named.Name != resource.Name {
+			continue
+		}
+		for _, property := range utils.ResolveSchema(document, named.Value).GetProperties().GetAdditionalProperties() {
+			if property.Name != field.Name {
+				continue
+			}
+			example := utils.ResolveSchema(document, property.Value).GetExample()
+			if example == nil {
+				return true
+			}
+			var name string
+			if err := yaml.Unmarshal([]byte(example.Yaml), &name); err != nil {
+				return false
+			}
+			return isResourceName(name, segments)
+		}
+	}
+	return true
+}
+
+// isResourceName returns true if 'name' consists of the collections of the pattern 'segments' and IDs, e.g.
+// "shelves/1/books/2" for "shelves/{shelf}/books/{book}".
+func isResourceName(name string, segments []string) bool {
+	parts := strings.Split(name, "/")
+	if len(parts) != len(segments) {
+		return false
+	}
+	for i, part := range parts {
+		if part == "" || (!isPathVariable(segments[i]) && part != segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isResourcePattern returns true if 'segments' consist of collection/{variable} pairs.
+func isResourcePattern(segments []string) bool {
+	if len(segments) == 0 || len(segments)%2 != 0 {
+		return false
+	}
+	for i, segment := range segments {
+		if segment == "" || isPathVariable(segment) != (i%2 == 1) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPathVariable(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func newResourceMessage(method *surface_v1.Method, text string) *plugins.Message {
+	return &plugins.Message{
+		Code:  "RESOURCES",
+		Level: plugins.Message_INFO,
+		Text:  text,
+		Keys:  []string{"paths", method.Path, strings.ToLower(method.Method)},
+	}
+}
+
+// setMessageOptions sets the (google.api.resource) option of 'message' if it is a resource.
+func (r *resourceAnnotations) setMessageOptions(message *dpb.DescriptorProto) error {
+	if r == nil || r.resources[message.GetName()] == nil {
+		return nil
+	}
+	message.Options = &dpb.MessageOptions{}
+	return proto.SetExtension(message.Options, annotations.E_Resource, r.resources[message.GetName()])
+}
+
+// setFieldOptions sets the (google.api.resource_reference) option of 'field' of 'message' if it holds the resource
+// name of a resource.
+func (r *resourceAnnotations) setFieldOptions(message *dpb.DescriptorProto, field *dpb.FieldDescriptorProto) error {
+	if r == nil || r.references[message.GetName()][field.GetName()] == "" {
+		return nil
+	}
+	if field.Options == nil {
+		field.Options = &dpb.FieldOptions{}
+	}
+	reference := &annotations.ResourceReference{Type: r.references[message.GetName()][field.GetName()]}
+	return proto.SetExtension(field.Options, annotations.E_ResourceReference, reference)
+}
+
+// setMethodOptions sets the (google.api.method_signature) option of 'method'.
+func (r *resourceAnnotations) setMethodOptions(method *surface_v1.Method, options *dpb.MethodOptions) error {
+	if r == nil || r.signatures[method] == "" {
+		return nil
+	}
+	return proto.SetExtension(options, annotations.E_MethodSignature, []string{r.signatures[method]})
+}
+
+// buildResourceDependencies returns the FileDescriptorProtos that define the AIP annotations and the names of the
+// files that have to be imported, if any annotations have been inferred.
+func buildResourceDependencies(renderer *Renderer, imports []string) (dependencies []*dpb.FileDescriptorProto, newImports []string, err error) {
+	r := renderer.resources
+	if r == nil {
+		return nil, nil, nil
+	}
+	importPaths := make([]string, 0)
+	if len(r.resources) > 0 {
+		importPaths = append(importPaths, resourceOptionFile)
+	}
+	if len(r.signatures) > 0 {
+		importPaths = append(importPaths, clientOptionFile)
+	}
+	for _, importPath := range importPaths {
+		if utils.Contains(imports, importPath) {
+			continue
+		}
+		files, err := loadFileDescriptorProtos(importPath, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		dependencies = appendMissingFiles(dependencies, files...)
+		newImports = append(newImports, importPath)
+	}
+	return dependencies, newImports, nil
+}
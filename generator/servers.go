@@ -0,0 +1,111 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+// The sources of the version of the proto package.
+const (
+	versionFromInfo   = "info"
+	versionFromServer = "server"
+)
+
+// infoVersionPattern matches the versions of info.version that can be converted into the version of a proto package,
+// e.g.: "1", "1.2.0", "v2beta1", "2.0.0-beta.1" or "1.0.0-alpha". Pre-releases other than alpha and beta are ignored.
+var infoVersionPattern = regexp.MustCompile(`^v?(\d+)(\.\d+)*(-?(alpha|beta)[.-]?(\d*))?([-+].*)?$`)
+
+// serverBasePath returns the path of the URL of the first server of 'document' without trailing slash, e.g.: "/v1" for
+// "https://library.example.com/v1/". Server variables are replaced with their default values. The result is empty if
+// the document has no server or the URL has no path.
+func serverBasePath(document *openapiv3.Document) string {
+	servers := document.GetServers()
+	if len(servers) == 0 {
+		return ""
+	}
+	return urlBasePath(servers[0])
+}
+
+// urlBasePath returns the path of the URL of 'server' without trailing slash.
+func urlBasePath(server *openapiv3.Server) string {
+	serverURL := server.Url
+	for _, variable := range server.GetVariables().GetAdditionalProperties() {
+		serverURL = strings.Replace(serverURL, "{"+variable.Name+"}", variable.GetValue().GetDefault(), -1)
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(u.Path, "/")
+}
+
+// apiVersion returns the version of the API that is appended to the proto package, e.g.: "v1" or "v2beta1". 'source'
+// is either "info" for info.version or "server" for the last segment of the server base path that is a version.
+func apiVersion(document *openapiv3.Document, source string) (string, error) {
+	switch source {
+	case versionFromInfo:
+		version := strings.ToLower(document.GetInfo().GetVersion())
+		match := infoVersionPattern.FindStringSubmatch(version)
+		if match == nil {
+			return "", fmt.Errorf("the version of the package can't be derived from info.version %q", version)
+		}
+		return "v" + match[1] + match[4] + match[5], nil
+	case versionFromServer:
+		segments := strings.Split(serverBasePath(document), "/")
+		for i := len(segments) - 1; i >= 0; i-- {
+			if versionPattern.MatchString(segments[i]) {
+				return segments[i], nil
+			}
+		}
+		return "", fmt.Errorf("the version of the package can't be derived from the base path %q of the servers",
+			serverBasePath(document))
+	}
+	return "", fmt.Errorf("unsupported source of the version: %s", source)
+}
+
+// versionedPackageName appends 'version' to 'packageName' unless the package already ends with a version, e.g.:
+// "acme.bookstore.v1" for "acme.bookstore" and "v1".
+func versionedPackageName(packageName string, version string) string {
+	parts := strings.Split(packageName, ".")
+	if versionPattern.MatchString(parts[len(parts)-1]) {
+		return packageName
+	}
+	return packageName + "." + version
+}
+
+// applyBasePath prefixes the server base path to the HttpRule template 'path' or strips it from 'path', depending on
+// PrefixBasePath and StripBasePath. Paths that already start with the base path aren't prefixed again.
+func (renderer *Renderer) applyBasePath(path string) string {
+	basePath := serverBasePath(renderer.Document)
+	if basePath == "" {
+		return path
+	}
+	hasBasePath := path == basePath || strings.HasPrefix(path, basePath+"/")
+	switch {
+	case renderer.PrefixBasePath && !hasBasePath:
+		return basePath + path
+	case renderer.StripBasePath && path == basePath:
+		return "/"
+	case renderer.StripBasePath && hasBasePath:
+		return strings.TrimPrefix(path, basePath)
+	}
+	return path
+}
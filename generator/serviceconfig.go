@@ -0,0 +1,223 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	plugins "github.com/google/gnostic/plugins"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/genproto/googleapis/api/serviceconfig"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gopkg.in/yaml.v3"
+)
+
+// The formats of the service configuration.
+const (
+	serviceConfigYAML = "yaml"
+	serviceConfigJSON = "json"
+)
+
+const (
+	// The specification extensions of a security scheme that make it a JWT authentication provider, as known from
+	// the OpenAPI descriptions of Cloud Endpoints.
+	extensionGoogleIssuer    = "x-google-issuer"
+	extensionGoogleJwksURI   = "x-google-jwks_uri"
+	extensionGoogleAudiences = "x-google-audiences"
+
+	// The suffix of an OpenID Connect discovery URL, which is removed to get the issuer.
+	openIdConfigurationSuffix = "/.well-known/openid-configuration"
+)
+
+// buildServiceConfig builds the google.api.Service configuration of the rendered .proto file, which ESP and API
+// gateways read alongside the descriptors:
+//   - name: the host of the first server or the package; title: info.title.
+//   - apis: the services of the .proto file.
+//   - http.rules: the HttpRules of the RPCs, which can replace their google.api.http options.
+//   - documentation: info.description and the summaries and descriptions of the operations.
+//   - authentication: the providers of the security schemes and the rules of the security requirements.
+//
+// Security schemes that aren't JWT providers are reported by the returned messages.
+func (renderer *Renderer) buildServiceConfig() (*serviceconfig.Service, []*plugins.Message) {
+	document := renderer.Document
+	config := &serviceconfig.Service{
+		Name:          resourceServiceName(renderer),
+		Title:         document.GetInfo().GetTitle(),
+		ConfigVersion: wrapperspb.UInt32(3),
+		Http:          &annotations.Http{},
+		Documentation: &serviceconfig.Documentation{Summary: strings.TrimSpace(document.GetInfo().GetDescription())},
+	}
+	providers, messages := buildAuthProviders(document)
+	authentication := &serviceconfig.Authentication{Providers: providers}
+	if rule := buildAuthenticationRule("*", document.GetSecurity(), providers); rule != nil {
+		authentication.Rules = append(authentication.Rules, rule)
+	}
+
+	file := getLast(renderer.FdSet.File)
+	for _, service := range file.Service {
+		serviceName := renderer.Package + "." + service.GetName()
+		config.Apis = append(config.Apis, &apipb.Api{Name: serviceName})
+		for _, rpc := range service.Method {
+			selector := serviceName + "." + rpc.GetName()
+			if extension, err := proto.GetExtension(rpc.Options, annotations.E_Http); err == nil {
+				rule := proto.Clone(extension.(*annotations.HttpRule)).(*annotations.HttpRule)
+				rule.Selector = selector
+				config.Http.Rules = append(config.Http.Rules, rule)
+			}
+			m := renderer.rpcMethods[selector]
+			if m == nil {
+				continue
+			}
+			operation := findOperation(document, m.Method, m.Path)
+			if description := operationDescription(operation); description != "" {
+				config.Documentation.Rules = append(config.Documentation.Rules,
+					&serviceconfig.DocumentationRule{Selector: selector, Description: description})
+			}
+			if rule := buildAuthenticationRule(selector, operation.GetSecurity(), providers); rule != nil {
+				authentication.Rules = append(authentication.Rules, rule)
+			}
+		}
+	}
+	if len(authentication.Providers) > 0 {
+		config.Authentication = authentication
+	}
+	return config, messages
+}
+
+// operationDescription returns the summary and the description of 'operation' separated by an empty line.
+func operationDescription(operation *openapiv3.Operation) string {
+	paragraphs := make([]string, 0)
+	for _, paragraph := range []string{operation.GetSummary(), operation.GetDescription()} {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// buildAuthProviders returns the JWT authentication providers of the security schemes of 'document', which are named
+// after their scheme. OpenID Connect schemes are providers whose issuer is the discovery URL without
+// "/.well-known/openid-configuration". Other schemes are providers if they have the extension x-google-issuer.
+func buildAuthProviders(document *openapiv3.Document) ([]*serviceconfig.AuthProvider, []*plugins.Message) {
+	providers := make([]*serviceconfig.AuthProvider, 0)
+	messages := make([]*plugins.Message, 0)
+	for _, namedScheme := range document.GetComponents().GetSecuritySchemes().GetAdditionalProperties() {
+		scheme := namedScheme.GetValue().GetSecurityScheme()
+		if scheme == nil {
+			continue
+		}
+		extensions := scheme.GetSpecificationExtension()
+		provider := &serviceconfig.AuthProvider{Id: namedScheme.Name}
+		provider.JwksUri, _ = stringExtension(extensions, extensionGoogleJwksURI)
+		provider.Audiences, _ = stringExtension(extensions, extensionGoogleAudiences)
+		if issuer, ok := stringExtension(extensions, extensionGoogleIssuer); ok {
+			provider.Issuer = issuer
+		} else if scheme.Type == "openIdConnect" {
+			provider.Issuer = strings.TrimSuffix(scheme.OpenIdConnectUrl, openIdConfigurationSuffix)
+		} else {
+			text := "The security scheme " + namedScheme.Name + " of type " + scheme.Type + " has no " +
+				extensionGoogleIssuer + ", it isn't an authentication provider of the service configuration."
+			msg := constructWarningMessage("SERVICECONFIG", text, []string{"components", "securitySchemes", namedScheme.Name})
+			messages = append(messages, &msg)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers, messages
+}
+
+// buildAuthenticationRule returns the rule of the RPCs of 'selector' that requires one of the providers of
+// 'requirements', or nil if they don't name a provider.
+func buildAuthenticationRule(selector string, requirements []*openapiv3.SecurityRequirement,
+	providers []*serviceconfig.AuthProvider) *serviceconfig.AuthenticationRule {
+	rule := &serviceconfig.AuthenticationRule{Selector: selector}
+	for _, requirement := range requirements {
+		for _, namedScopes := range requirement.GetAdditionalProperties() {
+			for _, provider := range providers {
+				if provider.Id == namedScopes.Name {
+					rule.Requirements = append(rule.Requirements,
+						&serviceconfig.AuthRequirement{ProviderId: provider.Id, Audiences: provider.Audiences})
+				}
+			}
+		}
+	}
+	if len(rule.Requirements) == 0 {
+		return nil
+	}
+	return rule
+}
+
+// RenderServiceConfig renders the google.api.Service configuration in 'format', which is either "yaml" or "json".
+// Its file is named after the .proto file 'protoFileName', e.g.: "bookstore_service.yaml" for "bookstore.proto".
+func (renderer *Renderer) RenderServiceConfig(protoFileName string, format string) (*plugins.File, []*plugins.Message, error) {
+	config, messages := renderer.buildServiceConfig()
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	// protojson doesn't guarantee stable whitespace.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, nil, err
+	}
+	var out bytes.Buffer
+	if format == serviceConfigJSON {
+		if err := json.Indent(&out, compact.Bytes(), "", "  "); err != nil {
+			return nil, nil, err
+		}
+		out.WriteString("\n")
+	} else {
+		if err := writeServiceConfigYAML(&out, compact.Bytes()); err != nil {
+			return nil, nil, err
+		}
+	}
+	fileName := strings.TrimSuffix(protoFileName, ".proto") + "_service." + format
+	return &plugins.File{Name: fileName, Data: out.Bytes()}, messages, nil
+}
+
+// writeServiceConfigYAML converts the JSON of a service configuration into YAML in block style, which starts with the
+// "type: google.api.Service" that the YAML configurations of Google APIs require.
+func writeServiceConfigYAML(out *bytes.Buffer, data []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+	root := node.Content[0]
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "type"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "google.api.Service"},
+	}, root.Content...)
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetYAMLStyle removes the flow style and the quotes that 'node' has from its JSON source.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	openapiv3 "github.com/google/gnostic/openapiv3"
+)
+
+// The specification extension of an operation that sets the streaming mode of the RPC: "server", "client" or "bidi".
+const extensionGrpcStreaming = "x-grpc-streaming"
+
+// Responses with these media types are a stream of messages.
+var streamingMediaTypes = []string{"text/event-stream", "application/x-ndjson", "application/jsonl"}
+
+// getStreamingForOperation returns whether the RPC of 'operation' streams its requests and/or responses. The
+// x-grpc-streaming extension takes precedence over the media types of the successful responses. 'operation' may be nil.
+func getStreamingForOperation(operation *openapiv3.Operation) (clientStreaming bool, serverStreaming bool, err error) {
+	if operation == nil {
+		return false, false, nil
+	}
+	if streaming, ok := stringExtension(operation.SpecificationExtension, extensionGrpcStreaming); ok {
+		switch streaming {
+		case "server":
+			return false, true, nil
+		case "client":
+			return true, false, nil
+		case "bidi":
+			return true, true, nil
+		default:
+			return false, false, fmt.Errorf("unsupported value for %s of operation %s: %s", extensionGrpcStreaming,
+				operation.OperationId, streaming)
+		}
+	}
+	for _, namedResponse := range operation.GetResponses().GetResponseOrReference() {
+		if !strings.HasPrefix(namedResponse.Name, "2") {
+			continue
+		}
+		for _, namedMediaType := range namedResponse.GetValue().GetResponse().GetContent().GetAdditionalProperties() {
+			if isStreamingMediaType(namedMediaType.Name) {
+				return false, true, nil
+			}
+		}
+	}
+	return false, false, nil
+}
+
+// isStreamingMediaType returns true if 'mediaType' (e.g.: "text/event-stream; charset=utf-8") is a stream of messages.
+func isStreamingMediaType(mediaType string) bool {
+	mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+	for _, streamingMediaType := range streamingMediaTypes {
+		if strings.EqualFold(mediaType, streamingMediaType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,493 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	dpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	openapiv3 "github.com/google/gnostic/openapiv3"
+	surface_v1 "github.com/google/gnostic/surface"
+	prDesc "github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"gopkg.in/yaml.v3"
+
+	"github.com/google/gnostic-grpc/utils"
+
+	// Register the descriptors of commonly used types, so they can be mapped without a .proto file on disk.
+	_ "google.golang.org/genproto/googleapis/type/color"
+	_ "google.golang.org/genproto/googleapis/type/date"
+	_ "google.golang.org/genproto/googleapis/type/datetime"
+	_ "google.golang.org/genproto/googleapis/type/decimal"
+	_ "google.golang.org/genproto/googleapis/type/interval"
+	_ "google.golang.org/genproto/googleapis/type/latlng"
+	_ "google.golang.org/genproto/googleapis/type/money"
+	_ "google.golang.org/genproto/googleapis/type/phone_number"
+	_ "google.golang.org/genproto/googleapis/type/postaladdress"
+	_ "google.golang.org/genproto/googleapis/type/timeofday"
+	_ "google.golang.org/protobuf/types/known/durationpb"
+	_ "google.golang.org/protobuf/types/known/structpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+	_ "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TypeMapping maps OpenAPI schemas onto an existing proto type. Exactly one of Schema, Ref and Format has to be set.
+type TypeMapping struct {
+	// The name of a component schema, e.g.: "Money".
+	Schema string `yaml:"schema"`
+	// A $ref URL, e.g.: "https://example.com/common.yaml#/components/schemas/Address".
+	Ref string `yaml:"ref"`
+	// The format of a scalar schema, e.g.: "date-time".
+	Format string `yaml:"format"`
+	// The fully qualified name of the proto type, e.g.: "google.type.Money".
+	ProtoType string `yaml:"type"`
+	// The import path of the .proto file that defines ProtoType, e.g.: "google/type/money.proto".
+	Import string `yaml:"import"`
+}
+
+// TypeMappings holds the configuration of all schemas that are mapped onto existing proto types. Messages are not
+// generated for mapped schemas, the generated .proto file imports the mapped types instead.
+type TypeMappings struct {
+	// Directories that are searched for imported .proto files. Files of commonly used packages (e.g. google/type/*
+	// and the well-known types) are found without them.
+	ProtoPaths []string `yaml:"proto_paths"`
+	// The mappings.
+	Mappings []*TypeMapping `yaml:"mappings"`
+}
+
+// LoadTypeMappings reads the type mapping configuration from the YAML file 'fileName', e.g.:
+//
+//	proto_paths: [protos]
+//	mappings:
+//	  - schema: Money
+//	    type: google.type.Money
+//	    import: google/type/money.proto
+//	  - format: date-time
+//	    type: google.protobuf.Timestamp
+//	    import: google/protobuf/timestamp.proto
+func LoadTypeMappings(fileName string) (*TypeMappings, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	typeMappings := &TypeMappings{}
+	if err := yaml.Unmarshal(b, typeMappings); err != nil {
+		return nil, err
+	}
+	for _, m := range typeMappings.Mappings {
+		if err := m.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return typeMappings, nil
+}
+
+func (m *TypeMapping) validate() error {
+	keys := 0
+	for _, key := range []string{m.Schema, m.Ref, m.Format} {
+		if key != "" {
+			keys++
+		}
+	}
+	if keys != 1 {
+		return errors.New("a type mapping needs exactly one of 'schema', 'ref' or 'format'")
+	}
+	if !isFullyQualifiedTypeName(m.ProtoType) || m.Import == "" {
+		return fmt.Errorf("the type mapping for %s%s%s needs a fully qualified 'type' and an 'import'",
+			m.Schema, m.Ref, m.Format)
+	}
+	return nil
+}
+
+// isFullyQualifiedTypeName returns true if 'name' references a proto type inside a package, e.g.: "google.type.Money".
+// Generated messages never contain a dot in their name.
+func isFullyQualifiedTypeName(name string) bool {
+	return strings.Contains(strings.TrimPrefix(name, "."), ".")
+}
+
+// applyTypeMappings removes the types of mapped schemas from 'model' and references the mapped proto types instead.
+// The $ref URLs of the fields are resolved from 'document', which may be nil.
+func applyTypeMappings(model *surface_v1.Model, typeMappings *TypeMappings, document *openapiv3.Document) {
+	if typeMappings == nil {
+		return
+	}
+	mappedTypes := make(map[string]string) // TypeName -> proto type
+	types := make([]*surface_v1.Type, 0)
+	for _, t := range model.Types {
+		if m := typeMappings.forSchema(t.Name); m != nil {
+			mappedTypes[t.TypeName] = m.ProtoType
+			continue
+		}
+		types = append(types, t)
+	}
+	model.Types = types
+
+	refs := collectFieldReferences(model, document)
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			if protoType, ok := mappedTypes[f.NativeType]; ok {
+				f.NativeType = protoType
+			} else if m := typeMappings.forField(f, refs[f]); m != nil {
+				f.NativeType = m.ProtoType
+				f.EnumValues = nil
+			}
+		}
+	}
+
+	for _, m := range model.Methods {
+		if protoType, ok := mappedTypes[m.ParametersTypeName]; ok {
+			m.ParametersTypeName = protoType
+		}
+		if protoType, ok := mappedTypes[m.ResponsesTypeName]; ok {
+			m.ResponsesTypeName = protoType
+		}
+	}
+}
+
+// forSchema returns the mapping for the component schema 'name' or nil.
+func (t *TypeMappings) forSchema(name string) *TypeMapping {
+	for _, m := range t.Mappings {
+		if m.Schema != "" && m.Schema == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// forField returns the mapping for a field that either references the mapped $ref URL 'ref' or has a mapped format.
+func (t *TypeMappings) forField(f *surface_v1.Field, ref string) *TypeMapping {
+	for _, m := range t.Mappings {
+		switch {
+		case m.Format != "" && f.Format == m.Format && f.Kind != surface_v1.FieldKind_MAP:
+			return m
+		case m.Ref != "" && ref == m.Ref &&
+			(f.Kind == surface_v1.FieldKind_REFERENCE || f.Kind == surface_v1.FieldKind_ARRAY):
+			return m
+		}
+	}
+	return nil
+}
+
+// coversSymbolicReference returns true if all references of 'document' into the OpenAPI description at 'url' are
+// mapped. In that case there is no need to generate a .proto file for that description.
+func (t *TypeMappings) coversSymbolicReference(document *openapiv3.Document, url string) bool {
+	if t == nil || document == nil {
+		return false
+	}
+	covered := false
+	for _, ref := range collectReferences(document.ToRawInfo()) {
+		if strings.Split(ref, "#")[0] != url {
+			continue
+		}
+		if !t.hasRef(ref) {
+			return false
+		}
+		covered = true
+	}
+	return covered
+}
+
+func (t *TypeMappings) hasRef(ref string) bool {
+	for _, m := range t.Mappings {
+		if m.Ref == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// collectFieldReferences returns the $ref URLs of the fields of 'model' that reference a schema (or whose items do).
+// The surface model only keeps the last segment of a $ref URL as type of a field, so "#/components/schemas/Money" and
+// "common.yaml#/components/schemas/Money" can only be told apart by looking the fields up in 'document'. The document
+// is walked along the types that the surface model created for it: the components, the request and response types of
+// the methods and the types their fields reference (e.g. inline objects, inline request bodies and responses).
+func collectFieldReferences(model *surface_v1.Model, document *openapiv3.Document) map[*surface_v1.Field]string {
+	refs := make(map[*surface_v1.Field]string)
+	if document == nil {
+		return refs
+	}
+	typesByName := make(map[string][]*surface_v1.Type)
+	typesByTypeName := make(map[string]*surface_v1.Type)
+	for _, t := range model.Types {
+		typesByName[t.Name] = append(typesByName[t.Name], t)
+		typesByTypeName[t.TypeName] = t
+	}
+	// Names aren't unique, e.g. the request body "Pet" and the schema "Pet" of the components are both named "Pet".
+	// Of those the type with the most fields of 'fieldNames' is chosen.
+	findType := func(name string, fieldNames []string) *surface_v1.Type {
+		var result *surface_v1.Type
+		matches := -1
+		for _, t := range typesByName[name] {
+			count := 0
+			for _, fieldName := range fieldNames {
+				if findField(t, fieldName) != nil {
+					count++
+				}
+			}
+			if count > matches {
+				result, matches = t, count
+			}
+		}
+		return result
+	}
+	var addSchema func(t *surface_v1.Type, schemaOrReference *openapiv3.SchemaOrReference)
+	// Inline objects (or arrays of them) are types of their own, which the field references by name.
+	addField := func(f *surface_v1.Field, schemaOrReference *openapiv3.SchemaOrReference) {
+		if ref := schemaReference(schemaOrReference); ref != "" {
+			refs[f] = ref
+		} else {
+			addSchema(findType(f.Type, propertyNames(schemaOrReference)), schemaOrReference)
+		}
+	}
+	addSchema = func(t *surface_v1.Type, schemaOrReference *openapiv3.SchemaOrReference) {
+		schema := schemaOrReference.GetSchema()
+		if t == nil || schema == nil {
+			return
+		}
+		if schema.Type == "array" {
+			for _, items := range schema.GetItems().GetSchemaOrReference() {
+				addSchema(t, items)
+			}
+			return
+		}
+		for _, property := range schema.GetProperties().GetAdditionalProperties() {
+			if f := findField(t, property.Name); f != nil {
+				addField(f, property.Value)
+			}
+		}
+	}
+	addContent := func(t *surface_v1.Type, content *openapiv3.MediaTypes) {
+		for _, namedMediaType := range content.GetAdditionalProperties() {
+			if f := findField(t, namedMediaType.Name); f != nil {
+				addField(f, namedMediaType.GetValue().GetSchema())
+			}
+		}
+	}
+	addParameters := func(t *surface_v1.Type, parameters []*openapiv3.ParameterOrReference) {
+		for _, parameter := range parameters {
+			if p := parameter.GetParameter(); p != nil {
+				if f := findField(t, p.Name); f != nil {
+					addField(f, p.Schema)
+				}
+			}
+		}
+	}
+	// The types of request bodies and responses are referenced by the fields of the request and response types.
+	addBody := func(t *surface_v1.Type, fieldName string, content *openapiv3.MediaTypes) {
+		if f := findField(t, fieldName); f != nil && content != nil {
+			addContent(findType(f.Type, mediaTypeNames(content)), content)
+		}
+	}
+
+	components := document.GetComponents()
+	for _, namedSchema := range components.GetSchemas().GetAdditionalProperties() {
+		addSchema(findType(namedSchema.Name, propertyNames(namedSchema.Value)), namedSchema.Value)
+	}
+	for _, namedParameter := range components.GetParameters().GetAdditionalProperties() {
+		parameter := namedParameter.GetValue().GetParameter()
+		addParameters(findType(namedParameter.Name, []string{parameter.GetName()}),
+			[]*openapiv3.ParameterOrReference{namedParameter.Value})
+	}
+	for _, namedResponse := range components.GetResponses().GetAdditionalProperties() {
+		content := namedResponse.GetValue().GetResponse().GetContent()
+		addContent(findType(namedResponse.Name, mediaTypeNames(content)), content)
+	}
+	for _, namedRequestBody := range components.GetRequestBodies().GetAdditionalProperties() {
+		content := namedRequestBody.GetValue().GetRequestBody().GetContent()
+		addContent(findType(namedRequestBody.Name, mediaTypeNames(content)), content)
+	}
+	for _, m := range model.Methods {
+		operation := findOperation(document, m.Method, m.Path)
+		if operation == nil {
+			continue
+		}
+		parameters := typesByTypeName[m.ParametersTypeName]
+		addParameters(parameters, operation.Parameters)
+		addBody(parameters, "request_body", operation.GetRequestBody().GetRequestBody().GetContent())
+		responses := typesByTypeName[m.ResponsesTypeName]
+		for _, namedResponse := range operation.GetResponses().GetResponseOrReference() {
+			addBody(responses, namedResponse.Name, namedResponse.GetValue().GetResponse().GetContent())
+		}
+		addBody(responses, "default", operation.GetResponses().GetDefault().GetResponse().GetContent())
+	}
+	return refs
+}
+
+// findField returns the field of 't' that has been created for the property, parameter or media type 'name'.
+func findField(t *surface_v1.Type, name string) *surface_v1.Field {
+	if t == nil {
+		return nil
+	}
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// propertyNames returns the names of the properties of the inline schema 'schemaOrReference' or of its items.
+func propertyNames(schemaOrReference *openapiv3.SchemaOrReference) []string {
+	names := make([]string, 0)
+	schema := schemaOrReference.GetSchema()
+	if schema.GetType() == "array" {
+		for _, items := range schema.GetItems().GetSchemaOrReference() {
+			return propertyNames(items)
+		}
+	}
+	for _, property := range schema.GetProperties().GetAdditionalProperties() {
+		names = append(names, property.Name)
+	}
+	return names
+}
+
+// mediaTypeNames returns the media types of 'content', e.g.: "application/json".
+func mediaTypeNames(content *openapiv3.MediaTypes) []string {
+	names := make([]string, 0)
+	for _, namedMediaType := range content.GetAdditionalProperties() {
+		names = append(names, namedMediaType.Name)
+	}
+	return names
+}
+
+// schemaReference returns the $ref URL of 'schemaOrReference' or of the items of an array, otherwise "".
+func schemaReference(schemaOrReference *openapiv3.SchemaOrReference) string {
+	if ref := schemaOrReference.GetReference(); ref != nil {
+		return ref.XRef
+	}
+	if schema := schemaOrReference.GetSchema(); schema != nil && schema.Type == "array" {
+		for _, items := range schema.GetItems().GetSchemaOrReference() {
+			return schemaReference(items)
+		}
+	}
+	return ""
+}
+
+// collectReferences returns the values of all '$ref' keys inside 'node'.
+func collectReferences(node *yaml.Node) (refs []string) {
+	if node == nil {
+		return refs
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+				refs = append(refs, node.Content[i+1].Value)
+			}
+		}
+	}
+	for _, child := range node.Content {
+		refs = append(refs, collectReferences(child)...)
+	}
+	return refs
+}
+
+// buildTypeMappingDependencies returns the FileDescriptorProtos of all mapped types that are used inside 'model'
+// (including their transitive dependencies) and the names of the files that have to be imported.
+func buildTypeMappingDependencies(model *surface_v1.Model, typeMappings *TypeMappings) (dependencies []*dpb.FileDescriptorProto, imports []string, err error) {
+	if typeMappings == nil {
+		return nil, nil, nil
+	}
+	usedTypes := usedTypeNames(model)
+	for _, m := range typeMappings.Mappings {
+		if !usedTypes[m.ProtoType] || utils.Contains(imports, m.Import) {
+			continue
+		}
+		files, err := loadFileDescriptorProtos(m.Import, typeMappings.ProtoPaths)
+		if err != nil {
+			return nil, nil, err
+		}
+		dependencies = appendMissingFiles(dependencies, files...)
+		imports = append(imports, m.Import)
+	}
+	return dependencies, imports, nil
+}
+
+// usedTypeNames returns the names of all types that are referenced by the fields and methods of 'model'.
+func usedTypeNames(model *surface_v1.Model) map[string]bool {
+	usedTypes := make(map[string]bool)
+	for _, t := range model.Types {
+		for _, f := range t.Fields {
+			usedTypes[strings.TrimPrefix(f.NativeType, ".")] = true
+		}
+	}
+	for _, m := range model.Methods {
+		usedTypes[m.ParametersTypeName] = true
+		usedTypes[m.ResponsesTypeName] = true
+	}
+	return usedTypes
+}
+
+// loadFileDescriptorProtos returns the FileDescriptorProto of the file 'importPath' and of all its transitive
+// dependencies, dependencies first. Files are looked up in the registry of linked-in proto packages and then in
+// 'protoPaths'.
+func loadFileDescriptorProtos(importPath string, protoPaths []string) ([]*dpb.FileDescriptorProto, error) {
+	if fd, err := protoregistry.GlobalFiles.FindFileByPath(importPath); err == nil {
+		return registeredFileDescriptorProtos(fd), nil
+	}
+
+	parser := protoparse.Parser{
+		ImportPaths: protoPaths,
+		LookupImportProto: func(name string) (*dpb.FileDescriptorProto, error) {
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return nil, err
+			}
+			return protodesc.ToFileDescriptorProto(fd), nil
+		},
+	}
+	fds, err := parser.ParseFiles(importPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s for the type mapping: %v", importPath, err)
+	}
+	return parsedFileDescriptorProtos(fds[0]), nil
+}
+
+func registeredFileDescriptorProtos(fd protoreflect.FileDescriptor) (files []*dpb.FileDescriptorProto) {
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		files = appendMissingFiles(files, registeredFileDescriptorProtos(imports.Get(i).FileDescriptor)...)
+	}
+	return appendMissingFiles(files, protodesc.ToFileDescriptorProto(fd))
+}
+
+func parsedFileDescriptorProtos(fd *prDesc.FileDescriptor) (files []*dpb.FileDescriptorProto) {
+	for _, dependency := range fd.GetDependencies() {
+		files = appendMissingFiles(files, parsedFileDescriptorProtos(dependency)...)
+	}
+	return appendMissingFiles(files, fd.AsFileDescriptorProto())
+}
+
+// appendMissingFiles appends the files of 'toAdd' to 'files' whose name is not yet contained in 'files'.
+func appendMissingFiles(files []*dpb.FileDescriptorProto, toAdd ...*dpb.FileDescriptorProto) []*dpb.FileDescriptorProto {
+	for _, fd := range toAdd {
+		exists := false
+		for _, existing := range files {
+			if existing.GetName() == fd.GetName() {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			files = append(files, fd)
+		}
+	}
+	return files
+}
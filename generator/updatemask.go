@@ -0,0 +1,69 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"github.com/google/gnostic-grpc/utils"
+	surface_v1 "github.com/google/gnostic/surface"
+
+	// Registers google/protobuf/field_mask.proto, which is imported for update masks.
+	_ "google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	// The name of the field of a PATCH request that holds the fields to update.
+	updateMaskFieldName = "update_mask"
+	fieldMaskTypeName   = "google.protobuf.FieldMask"
+)
+
+// addUpdateMasks adds an update_mask field (https://google.aip.dev/134) to the requests of PATCH methods whose body
+// references the message of a component schema. Clients send the mask as query parameter (e.g.
+// "?update_mask=title,author"), so servers can distinguish fields that are set to their zero value from fields that
+// haven't been provided. It has to be called before inlineRequestBodies, the body remains a field of the request.
+func addUpdateMasks(renderer *Renderer) {
+	if !renderer.UpdateMasks {
+		return
+	}
+	model := renderer.Model
+	components := make([]string, 0)
+	for _, named := range renderer.Document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		components = append(components, named.Name)
+	}
+	for _, m := range model.Methods {
+		request := model.TypeWithTypeName(m.ParametersTypeName)
+		if m.Method != "PATCH" || request == nil {
+			continue
+		}
+		hasResourceBody, hasUpdateMask := false, false
+		for _, f := range request.Fields {
+			if f.Position == surface_v1.Position_BODY && f.Kind == surface_v1.FieldKind_REFERENCE {
+				body := model.TypeWithTypeName(f.NativeType)
+				hasResourceBody = body != nil && (renderer.Document == nil || utils.Contains(components, body.Name))
+			}
+			hasUpdateMask = hasUpdateMask || f.FieldName == updateMaskFieldName
+		}
+		if !hasResourceBody || hasUpdateMask {
+			continue
+		}
+		request.Fields = append(request.Fields, &surface_v1.Field{
+			Name:       updateMaskFieldName,
+			FieldName:  updateMaskFieldName,
+			Type:       fieldMaskTypeName,
+			NativeType: fieldMaskTypeName,
+			Kind:       surface_v1.FieldKind_REFERENCE,
+			Position:   surface_v1.Position_QUERY,
+		})
+	}
+}
@@ -138,12 +138,15 @@ func newIncompatibilityDescription(line int, column int, class IncompatibiltiyCl
 func classificationSeverity(classification IncompatibiltiyClassification) Severity {
 	var severityLevel Severity
 	switch classification {
-	case IncompatibiltiyClassification_IncompatibiltiyClassification_Default:
+	case IncompatibiltiyClassification_IncompatibiltiyClassification_Default,
+		IncompatibiltiyClassification_RepresentableParameterStyling:
 		severityLevel = Severity_INFO
 	case IncompatibiltiyClassification_Security,
 		IncompatibiltiyClassification_ParameterStyling,
 		IncompatibiltiyClassification_DataValidation,
-		IncompatibiltiyClassification_ExternalTranscodingSupport:
+		IncompatibiltiyClassification_ExternalTranscodingSupport,
+		IncompatibiltiyClassification_CustomHttpMethod,
+		IncompatibiltiyClassification_FormContent:
 		severityLevel = Severity_WARNING
 	case IncompatibiltiyClassification_InvalidOperation,
 		IncompatibiltiyClassification_InvalidDataState,
@@ -165,10 +168,22 @@ func classificationHint(classification IncompatibiltiyClassification) string {
 		reason = "gRPC HTTP/JSON transcoding not concerned with auth information."
 	case IncompatibiltiyClassification_ParameterStyling:
 		reason = "parameter styling not representable in .proto files."
+	case IncompatibiltiyClassification_RepresentableParameterStyling:
+		reason = "parameter styling that differs from gRPC HTTP/JSON transcoding but is representable in .proto " +
+			"files. Exploded form objects are flattened into the request message, deepObject parameters become " +
+			"nested messages that are sent as dotted query parameters (filter.name instead of filter[name])."
 	case IncompatibiltiyClassification_DataValidation:
 		reason = "dataValidation (regex, array limits, etc.) not natively supported in .proto files."
 	case IncompatibiltiyClassification_ExternalTranscodingSupport:
 		reason = "the need for external transcoding support outside of .proto files"
+	case IncompatibiltiyClassification_CustomHttpMethod:
+		reason = "HTTP methods (head, options, trace) only representable as custom patterns in .proto files. " +
+			"Custom kinds are honoured by Envoy's gRPC-JSON transcoder, ESPv2 and grpc-gateway, other proxies " +
+			"may ignore them or answer such requests (e.g. CORS preflights) themselves."
+	case IncompatibiltiyClassification_FormContent:
+		reason = "form bodies (application/x-www-form-urlencoded, multipart/form-data) not decoded by gRPC " +
+			"HTTP/JSON transcoding. They are passed through as google.api.HttpBody, the form fields are only " +
+			"documented in comments."
 	case IncompatibiltiyClassification_InvalidOperation:
 		reason = "unstandard operation not fundamentally and truly supported in .proto represenation."
 	case IncompatibiltiyClassification_InvalidDataState:
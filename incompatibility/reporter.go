@@ -17,6 +17,7 @@ package incompatibility
 import (
 	"strconv"
 
+	"github.com/google/gnostic-grpc/utils"
 	openapiv3 "github.com/google/gnostic/openapiv3"
 )
 
@@ -72,30 +73,36 @@ func PathsSearch(doc *openapiv3.Document) []*Incompatibility {
 		path := pathItem.Value
 		if path.Head != nil {
 			incompatibilities = append(incompatibilities,
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, extendPath(pathKey, "head")...))
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, extendPath(pathKey, "head")...))
 		}
 		if path.Options != nil {
 			incompatibilities = append(incompatibilities,
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, extendPath(pathKey, "options")...))
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, extendPath(pathKey, "options")...))
 		}
 		if path.Trace != nil {
 			incompatibilities = append(incompatibilities,
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, extendPath(pathKey, "trace")...))
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, extendPath(pathKey, "trace")...))
 		}
 		incompatibilities = append(incompatibilities,
-			validOperationSearch(path.Get, extendPath(pathKey, "get"))...)
+			validOperationSearch(doc, path.Get, extendPath(pathKey, "get"))...)
 		incompatibilities = append(incompatibilities,
-			validOperationSearch(path.Put, extendPath(pathKey, "put"))...)
+			validOperationSearch(doc, path.Put, extendPath(pathKey, "put"))...)
 		incompatibilities = append(incompatibilities,
-			validOperationSearch(path.Post, extendPath(pathKey, "post"))...)
+			validOperationSearch(doc, path.Post, extendPath(pathKey, "post"))...)
 		incompatibilities = append(incompatibilities,
-			validOperationSearch(path.Delete, extendPath(pathKey, "delete"))...)
+			validOperationSearch(doc, path.Delete, extendPath(pathKey, "delete"))...)
 		incompatibilities = append(incompatibilities,
-			validOperationSearch(path.Patch, extendPath(pathKey, "patch"))...)
+			validOperationSearch(doc, path.Patch, extendPath(pathKey, "patch"))...)
 
+		operations := make([]*openapiv3.Operation, 0)
+		for _, operation := range []*openapiv3.Operation{path.Get, path.Put, path.Post, path.Delete, path.Patch} {
+			if operation != nil {
+				operations = append(operations, operation)
+			}
+		}
 		for ind, paramOrRef := range path.Parameters {
 			incompatibilities = append(incompatibilities,
-				parametersSearch(paramOrRef.GetParameter(), extendPath(pathKey, "parameters", strconv.Itoa(ind)))...)
+				parametersSearch(doc, operations, paramOrRef.GetParameter(), extendPath(pathKey, "parameters", strconv.Itoa(ind)))...)
 		}
 	}
 	return incompatibilities
@@ -128,7 +135,8 @@ func ComponentsSearch(doc *openapiv3.Document) []*Incompatibility {
 	if doc.Components.Parameters != nil {
 		for _, paramRef := range doc.Components.Parameters.GetAdditionalProperties() {
 			incompatibilities = append(incompatibilities,
-				parametersSearch(paramRef.GetValue().GetParameter(), extendPath(path, "parameters", paramRef.Name))...,
+				parametersSearch(doc, referencingOperations(doc, "#/components/parameters/"+paramRef.Name),
+					paramRef.GetValue().GetParameter(), extendPath(path, "parameters", paramRef.Name))...,
 			)
 		}
 	}
@@ -156,7 +164,7 @@ func ComponentsSearch(doc *openapiv3.Document) []*Incompatibility {
 // ========================= Helper Functions ======================== //
 
 // validOperationSearch scans for incompatibilities within valid operations
-func validOperationSearch(operation *openapiv3.Operation, path []string) []*Incompatibility {
+func validOperationSearch(doc *openapiv3.Document, operation *openapiv3.Operation, path []string) []*Incompatibility {
 	var incompatibilities []*Incompatibility
 	if operation == nil {
 		return incompatibilities
@@ -170,25 +178,29 @@ func validOperationSearch(operation *openapiv3.Operation, path []string) []*Inco
 			newIncompatibility(IncompatibiltiyClassification_Security, extendPath(path, "security")...))
 	}
 	for ind, paramOrRef := range operation.Parameters {
-		incompatibilities = append(incompatibilities, parametersSearch(paramOrRef.GetParameter(), extendPath(path, "parameters", strconv.Itoa(ind)))...)
+		incompatibilities = append(incompatibilities, parametersSearch(doc, []*openapiv3.Operation{operation},
+			paramOrRef.GetParameter(), extendPath(path, "parameters", strconv.Itoa(ind)))...)
 	}
 	return incompatibilities
 
 }
 
-// pathsSearch scans for incompatibilities within a parameters object
-func parametersSearch(param *openapiv3.Parameter, path []string) []*Incompatibility {
+// parametersSearch scans for incompatibilities within a parameters object, which is used by 'operations'
+func parametersSearch(doc *openapiv3.Document, operations []*openapiv3.Operation, param *openapiv3.Parameter,
+	path []string) []*Incompatibility {
 	var incompatibilities []*Incompatibility
 	if param == nil {
 		return incompatibilities
 	}
+	styling := IncompatibiltiyClassification_ParameterStyling
+	if isRepresentableStyle(doc, operations, param) {
+		styling = IncompatibiltiyClassification_RepresentableParameterStyling
+	}
 	if param.Style != "" {
-		incompatibilities = append(incompatibilities,
-			newIncompatibility(IncompatibiltiyClassification_ParameterStyling, extendPath(path, "style")...))
+		incompatibilities = append(incompatibilities, newIncompatibility(styling, extendPath(path, "style")...))
 	}
 	if param.Explode {
-		incompatibilities = append(incompatibilities,
-			newIncompatibility(IncompatibiltiyClassification_ParameterStyling, extendPath(path, "explode")...))
+		incompatibilities = append(incompatibilities, newIncompatibility(styling, extendPath(path, "explode")...))
 	}
 	if param.AllowReserved {
 		incompatibilities = append(incompatibilities,
@@ -205,6 +217,57 @@ func parametersSearch(param *openapiv3.Parameter, path []string) []*Incompatibil
 	return incompatibilities
 }
 
+// isRepresentableStyle returns true if the style of the query parameter 'param' is representable in .proto files:
+// form parameters map onto (repeated) fields, deepObject parameters map onto nested messages. Exploded form objects
+// are only representable if the generator flattens them into the request messages of all 'operations'.
+func isRepresentableStyle(doc *openapiv3.Document, operations []*openapiv3.Operation, param *openapiv3.Parameter) bool {
+	if param.In != "query" {
+		return false
+	}
+	switch {
+	case param.Style == "deepObject":
+		return true
+	case param.Style != "" && param.Style != "form":
+		return false
+	case !utils.IsExplodedQueryObject(doc, param):
+		return true
+	case len(operations) == 0:
+		return utils.IsFlattenedQueryObject(doc, nil, param)
+	}
+	for _, operation := range operations {
+		if !utils.IsFlattenedQueryObject(doc, operation, param) {
+			return false
+		}
+	}
+	return true
+}
+
+// referencingOperations returns the operations of 'doc' whose parameters or the parameters of whose path items include
+// the reference 'ref'.
+func referencingOperations(doc *openapiv3.Document, ref string) []*openapiv3.Operation {
+	operations := make([]*openapiv3.Operation, 0)
+	for _, pathItem := range doc.GetPaths().GetPath() {
+		item := pathItem.Value
+		itemReferences := hasParameterReference(item.Parameters, ref)
+		for _, operation := range []*openapiv3.Operation{item.Get, item.Put, item.Post, item.Delete, item.Patch} {
+			if operation != nil && (itemReferences || hasParameterReference(operation.Parameters, ref)) {
+				operations = append(operations, operation)
+			}
+		}
+	}
+	return operations
+}
+
+// hasParameterReference returns true if 'parameters' include the reference 'ref'.
+func hasParameterReference(parameters []*openapiv3.ParameterOrReference, ref string) bool {
+	for _, paramOrRef := range parameters {
+		if paramOrRef.GetReference().GetXRef() == ref {
+			return true
+		}
+	}
+	return false
+}
+
 // schemaSearch scans for incompatibilities within a schema object
 func schemaSearch(schema *openapiv3.Schema, path []string) []*Incompatibility {
 	var incompatibilities []*Incompatibility
@@ -317,7 +380,7 @@ func responseSearch(resp *openapiv3.Response, path []string) []*Incompatibility
 	if resp.Content != nil {
 		for _, prop := range resp.Content.AdditionalProperties {
 			incompatibilities = append(incompatibilities,
-				contentSearch(prop.Value, extendPath(path, prop.Name))...,
+				contentSearch(prop.Name, prop.Value, extendPath(path, prop.Name))...,
 			)
 		}
 	}
@@ -332,16 +395,20 @@ func headerSearch(headerName string, header *openapiv3.Header, path []string) []
 	}
 	paramEquiv := header2Paramter(headerName, header)
 	incompatibilities = append(incompatibilities,
-		parametersSearch(paramEquiv, path)...)
+		parametersSearch(nil, nil, paramEquiv, path)...)
 	return incompatibilities
 }
 
-// contentSearch scans for incompatibilities in a media object
-func contentSearch(media *openapiv3.MediaType, path []string) []*Incompatibility {
+// contentSearch scans for incompatibilities in a media object of the media type 'mediaType'
+func contentSearch(mediaType string, media *openapiv3.MediaType, path []string) []*Incompatibility {
 	var incompatibilities []*Incompatibility
 	if media == nil {
 		return incompatibilities
 	}
+	if utils.IsFormMediaType(mediaType) {
+		incompatibilities = append(incompatibilities,
+			newIncompatibility(IncompatibiltiyClassification_FormContent, path...))
+	}
 	if media.Encoding != nil {
 		incompatibilities = append(incompatibilities,
 			newIncompatibility(IncompatibiltiyClassification_ParameterStyling, extendPath(path, "encoding")...))
@@ -363,7 +430,7 @@ func requestBodySearch(req *openapiv3.RequestBody, path []string) []*Incompatibi
 	if req.Content != nil {
 		for _, namedContent := range req.Content.GetAdditionalProperties() {
 			incompatibilities = append(incompatibilities,
-				contentSearch(namedContent.Value, extendPath(path, namedContent.Name))...,
+				contentSearch(namedContent.Name, namedContent.Value, extendPath(path, namedContent.Name))...,
 			)
 		}
 	}
@@ -101,9 +101,9 @@ func TestPathsSearch(t *testing.T) {
 			&openapiv3.Document{
 				Paths: makeShallowPathsObject("pathName", OPTIONS, HEAD, TRACE)},
 			makeIncompatibilityReport(
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, "paths", "pathName", "options"),
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, "paths", "pathName", "head"),
-				newIncompatibility(IncompatibiltiyClassification_InvalidOperation, "paths", "pathName", "trace"),
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, "paths", "pathName", "options"),
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, "paths", "pathName", "head"),
+				newIncompatibility(IncompatibiltiyClassification_CustomHttpMethod, "paths", "pathName", "trace"),
 			),
 		},
 	}
@@ -194,7 +194,7 @@ func TestOperationSearch(t *testing.T) {
 		},
 	}
 	for _, trial := range operationSearchTest {
-		got := validOperationSearch(trial.operation, []string{})
+		got := validOperationSearch(nil, trial.operation, []string{})
 		t.Run(trial.testname, func(tt *testing.T) {
 			errorString := fmt.Sprintf("validOperationSearch(%v): diff(-want +got):\n", trial.operation)
 			testIncompatibilityReports(tt, errorString, trial.expectedIncompatibilityReport,
@@ -204,14 +204,26 @@ func TestOperationSearch(t *testing.T) {
 }
 
 func TestParametersSearch(t *testing.T) {
+	pageSchema := func(propertyType string) *openapiv3.SchemaOrReference {
+		return &openapiv3.SchemaOrReference{Oneof: &openapiv3.SchemaOrReference_Schema{Schema: &openapiv3.Schema{
+			Type: "object",
+			Properties: &openapiv3.Properties{AdditionalProperties: []*openapiv3.NamedSchemaOrReference{{
+				Name: "size",
+				Value: &openapiv3.SchemaOrReference{Oneof: &openapiv3.SchemaOrReference_Schema{
+					Schema: &openapiv3.Schema{Type: propertyType}}},
+			}}},
+		}}}
+	}
 	var parameterSearchTest = []struct {
 		testname                      string
 		parameter                     *openapiv3.Parameter
+		operation                     *openapiv3.Operation
 		expectedIncompatibilityReport *IncompatibilityReport
 	}{
 		{
 			"emptyparameter",
 			&openapiv3.Parameter{},
+			nil,
 			makeIncompatibilityReport(),
 		},
 		{
@@ -220,6 +232,7 @@ func TestParametersSearch(t *testing.T) {
 				Name:     "name",
 				Required: true,
 			},
+			nil,
 			makeIncompatibilityReport(),
 		},
 		{
@@ -231,6 +244,7 @@ func TestParametersSearch(t *testing.T) {
 				AllowReserved:   true,
 				Schema:          &openapiv3.SchemaOrReference{},
 			},
+			nil,
 			makeIncompatibilityReport(
 				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "style"),
 				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "explode"),
@@ -238,9 +252,78 @@ func TestParametersSearch(t *testing.T) {
 				newIncompatibility(IncompatibiltiyClassification_DataValidation, "allowEmptyValue"),
 			),
 		},
+		{
+			"RepresentableStyles",
+			&openapiv3.Parameter{
+				In:      "query",
+				Style:   "deepObject",
+				Explode: true,
+			},
+			nil,
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_RepresentableParameterStyling, "style"),
+				newIncompatibility(IncompatibiltiyClassification_RepresentableParameterStyling, "explode"),
+			),
+		},
+		{
+			"UnrepresentableQueryStyle",
+			&openapiv3.Parameter{
+				In:    "query",
+				Style: "pipeDelimited",
+			},
+			nil,
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "style"),
+			),
+		},
+		{
+			"FlattenedQueryObject",
+			&openapiv3.Parameter{
+				Name:    "page",
+				In:      "query",
+				Explode: true,
+				Schema:  pageSchema("integer"),
+			},
+			nil,
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_RepresentableParameterStyling, "explode"),
+			),
+		},
+		{
+			"QueryObjectWithNestedProperty",
+			&openapiv3.Parameter{
+				Name:    "page",
+				In:      "query",
+				Explode: true,
+				Schema:  pageSchema("object"),
+			},
+			nil,
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "explode"),
+			),
+		},
+		{
+			"QueryObjectCollidingWithParameter",
+			&openapiv3.Parameter{
+				Name:    "page",
+				In:      "query",
+				Explode: true,
+				Schema:  pageSchema("integer"),
+			},
+			&openapiv3.Operation{Parameters: []*openapiv3.ParameterOrReference{{
+				Oneof: &openapiv3.ParameterOrReference_Parameter{Parameter: &openapiv3.Parameter{Name: "size", In: "query"}},
+			}}},
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "explode"),
+			),
+		},
 	}
 	for _, trial := range parameterSearchTest {
-		got := parametersSearch(trial.parameter, []string{})
+		operations := make([]*openapiv3.Operation, 0)
+		if trial.operation != nil {
+			operations = append(operations, trial.operation)
+		}
+		got := parametersSearch(nil, operations, trial.parameter, []string{})
 		t.Run(trial.testname, func(tt *testing.T) {
 			errorString := fmt.Sprintf("parametersSearch(%v): diff(-want +got):\n", trial.parameter)
 			testIncompatibilityReports(tt, errorString, trial.expectedIncompatibilityReport,
@@ -376,3 +459,54 @@ func TestResponseSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestBodySearch(t *testing.T) {
+	var requestBodySearchTest = []struct {
+		testname                      string
+		requestBody                   *openapiv3.RequestBody
+		expectedIncompatibilityReport *IncompatibilityReport
+	}{
+		{
+			"emptyschema",
+			&openapiv3.RequestBody{},
+			makeIncompatibilityReport(),
+		},
+		{
+			"JSONContent",
+			&openapiv3.RequestBody{
+				Content: &openapiv3.MediaTypes{
+					AdditionalProperties: []*openapiv3.NamedMediaType{
+						{Name: "application/json", Value: &openapiv3.MediaType{}},
+					},
+				},
+			},
+			makeIncompatibilityReport(),
+		},
+		{
+			"FormContent",
+			&openapiv3.RequestBody{
+				Content: &openapiv3.MediaTypes{
+					AdditionalProperties: []*openapiv3.NamedMediaType{
+						{Name: "application/x-www-form-urlencoded", Value: &openapiv3.MediaType{}},
+						{Name: "multipart/form-data", Value: &openapiv3.MediaType{
+							Encoding: &openapiv3.Encodings{},
+						}},
+					},
+				},
+			},
+			makeIncompatibilityReport(
+				newIncompatibility(IncompatibiltiyClassification_FormContent, "application/x-www-form-urlencoded"),
+				newIncompatibility(IncompatibiltiyClassification_FormContent, "multipart/form-data"),
+				newIncompatibility(IncompatibiltiyClassification_ParameterStyling, "multipart/form-data", "encoding"),
+			),
+		},
+	}
+	for _, trial := range requestBodySearchTest {
+		got := requestBodySearch(trial.requestBody, []string{})
+		t.Run(trial.testname, func(tt *testing.T) {
+			errorString := fmt.Sprintf("requestBodySearch(%v): diff(-want +got):\n", trial.requestBody)
+			testIncompatibilityReports(tt, errorString, trial.expectedIncompatibilityReport,
+				&IncompatibilityReport{Incompatibilities: got})
+		})
+	}
+}
@@ -26,20 +26,30 @@ import (
 func main() {
 	env, err := plugins.NewEnvironment()
 	env.RespondAndExitIfError(err)
-	switch paramLen := len(env.Request.Parameters); paramLen {
-	case 0:
+	if !hasReportParameter(env) {
+		// All other parameters customize the generated .proto file.
 		generator.RunProtoGenerator(env)
+		return
+	}
+	switch paramLen := len(env.Request.Parameters); paramLen {
 	case 1:
 		resolveModeFromParameters(env)
 	default:
-		exitWithMessage(env, "This plugin supports at most one parameter during an invocation")
+		exitWithMessage(env, "The 'report' parameter can't be combined with other parameters")
 	}
 }
 
-func resolveModeFromParameters(env *plugins.Environment) {
-	if env.Request.Parameters[0].Name != "report" {
-		exitWithMessage(env, "unsupported parameter name")
+// hasReportParameter returns true if the plugin is invoked to generate an incompatibility report.
+func hasReportParameter(env *plugins.Environment) bool {
+	for _, p := range env.Request.Parameters {
+		if p.Name == "report" {
+			return true
+		}
 	}
+	return false
+}
+
+func resolveModeFromParameters(env *plugins.Environment) {
 	switch env.Request.Parameters[0].Value {
 	case "1": // Base incompatibility scanning
 		incompatibility.GnosticIncompatibiltyScanning(env, incompatibility.BaseIncompatibility_Report)
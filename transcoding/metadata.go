@@ -0,0 +1,190 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcoding
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// The default header matcher of grpc-gateway (runtime.DefaultHeaderMatcher) forwards permanent HTTP headers (e.g.
+// "Cookie") with the prefix "grpcgateway-" and headers with the prefix "Grpc-Metadata-" without that prefix, other
+// headers are dropped. Envoy forwards all headers as they are.
+const (
+	grpcGatewayMetadataPrefix = "grpcgateway-"
+	grpcMetadataHeaderPrefix  = "grpc-metadata-"
+)
+
+type metadataValuesKey struct{}
+
+// UnaryServerInterceptor extracts the header and cookie parameters of an RPC from the incoming gRPC metadata. The
+// parameters are declared by the (gnostic.grpc.metadata) option of the method, which is generated with the parameter
+// headers=metadata. Requests that lack a required parameter or have a value of the wrong type are rejected with
+// codes.InvalidArgument. The handler reads the values with MetadataValues.
+//
+// grpc-gateway only forwards header parameters that clients send with the prefix "Grpc-Metadata-" (e.g.
+// "Grpc-Metadata-X-Request-Id"), unless its ServeMux is created with runtime.WithIncomingHeaderMatcher and a matcher
+// that forwards them. Cookies are always forwarded.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := withMetadataValues(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the UnaryServerInterceptor of streaming RPCs. The handler reads the values with
+// MetadataValues from the context of its stream.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := withMetadataValues(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &metadataServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// metadataServerStream is a grpc.ServerStream whose context holds the extracted metadata values.
+type metadataServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *metadataServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// withMetadataValues extracts the header and cookie parameters of the method 'fullMethod' from the incoming metadata
+// of 'ctx' and returns a context that holds their values.
+func withMetadataValues(ctx context.Context, fullMethod string) (context.Context, error) {
+	contract := MetadataForMethod(fullMethod)
+	if len(contract) == 0 {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values, err := ExtractMetadata(md, contract)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, metadataValuesKey{}, values), nil
+}
+
+// MetadataValues returns the values of the header and cookie parameters that have been extracted by
+// UnaryServerInterceptor or StreamServerInterceptor, keyed by the name of the parameter.
+func MetadataValues(ctx context.Context) map[string]string {
+	values, _ := ctx.Value(metadataValuesKey{}).(map[string]string)
+	return values
+}
+
+// MetadataForMethod returns the (gnostic.grpc.metadata) option of the method 'fullMethod' (e.g.:
+// "/bookstore.Bookstore/GetBook"), which has to be registered in protoregistry.GlobalFiles.
+func MetadataForMethod(fullMethod string) []*Metadata {
+	name := strings.Replace(strings.TrimPrefix(fullMethod, "/"), "/", ".", 1)
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil
+	}
+	method, ok := descriptor.(protoreflect.MethodDescriptor)
+	if !ok || method.Options() == nil {
+		return nil
+	}
+	contract, _ := proto.GetExtension(method.Options(), E_Metadata).([]*Metadata)
+	return contract
+}
+
+// ExtractMetadata returns the values of the header and cookie parameters of 'contract' in 'md', keyed by the name of
+// the parameter. An error with codes.InvalidArgument is returned if a required parameter is missing or if a value
+// doesn't match the type of its parameter.
+func ExtractMetadata(md metadata.MD, contract []*Metadata) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, m := range contract {
+		value, ok := "", false
+		switch m.Source {
+		case Metadata_HEADER:
+			value, ok = headerValue(md, m.Name)
+		case Metadata_COOKIE:
+			value, ok = cookieValue(md, m.Name)
+		}
+		if !ok {
+			if m.Required {
+				return nil, status.Errorf(codes.InvalidArgument, "missing %s %s", sourceName(m.Source), m.Name)
+			}
+			continue
+		}
+		if !isValidValue(value, m.Type) {
+			return nil, status.Errorf(codes.InvalidArgument, "the %s %s must be of type %s: %s",
+				sourceName(m.Source), m.Name, m.Type, value)
+		}
+		values[m.Name] = value
+	}
+	return values, nil
+}
+
+// headerValue returns the first value of the header 'name', which may have been forwarded with one of the prefixes of
+// grpc-gateway.
+func headerValue(md metadata.MD, name string) (string, bool) {
+	for _, key := range []string{name, grpcGatewayMetadataPrefix + name, grpcMetadataHeaderPrefix + name} {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0], true
+		}
+	}
+	return "", false
+}
+
+// cookieValue returns the value of the cookie 'name', which is sent in the "Cookie" header.
+func cookieValue(md metadata.MD, name string) (string, bool) {
+	header := http.Header{}
+	for _, key := range []string{"cookie", grpcGatewayMetadataPrefix + "cookie"} {
+		for _, value := range md.Get(key) {
+			header.Add("Cookie", value)
+		}
+	}
+	cookie, err := (&http.Request{Header: header}).Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// isValidValue returns true if 'value' is a valid value of the OpenAPI type 'valueType'.
+func isValidValue(value string, valueType string) bool {
+	var err error
+	switch valueType {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
+
+func sourceName(source Metadata_Source) string {
+	return strings.ToLower(source.String())
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcoding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	contract := []*Metadata{
+		{Name: "X-Request-Id", Source: Metadata_HEADER, Type: "string", Required: true},
+		{Name: "X-Page-Size", Source: Metadata_HEADER, Type: "integer"},
+		{Name: "session", Source: Metadata_COOKIE, Type: "string"},
+	}
+	var extractTests = []struct {
+		testname string
+		md       metadata.MD
+		expected map[string]string
+		code     codes.Code
+	}{
+		{
+			"AllValues",
+			metadata.Pairs("x-request-id", "1", "x-page-size", "10", "cookie", "session=abc; theme=dark"),
+			map[string]string{"X-Request-Id": "1", "X-Page-Size": "10", "session": "abc"},
+			codes.OK,
+		},
+		{
+			"GrpcGatewayPrefix",
+			metadata.Pairs("grpcgateway-x-request-id", "1", "grpcgateway-cookie", "session=abc"),
+			map[string]string{"X-Request-Id": "1", "session": "abc"},
+			codes.OK,
+		},
+		{
+			"GrpcMetadataPrefix",
+			metadata.Pairs("grpc-metadata-x-request-id", "1"),
+			map[string]string{"X-Request-Id": "1"},
+			codes.OK,
+		},
+		{
+			"MissingRequiredHeader",
+			metadata.Pairs("x-page-size", "10"),
+			nil,
+			codes.InvalidArgument,
+		},
+		{
+			"InvalidType",
+			metadata.Pairs("x-request-id", "1", "x-page-size", "ten"),
+			nil,
+			codes.InvalidArgument,
+		},
+	}
+	for _, trial := range extractTests {
+		t.Run(trial.testname, func(tt *testing.T) {
+			values, err := ExtractMetadata(trial.md, contract)
+			if status.Code(err) != trial.code {
+				tt.Fatalf("Expected the code %s, got %s", trial.code, status.Code(err))
+			}
+			if diff := cmp.Diff(trial.expected, values); err == nil && diff != "" {
+				tt.Errorf("ExtractMetadata: diff(-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExtractMetadataFromGrpcGateway(t *testing.T) {
+	contract := []*Metadata{
+		{Name: "X-Request-Id", Source: Metadata_HEADER, Type: "string", Required: true},
+		{Name: "session", Source: Metadata_COOKIE, Type: "string"},
+	}
+	forwardAll := func(key string) (string, bool) {
+		return key, true
+	}
+	var gatewayTests = []struct {
+		testname string
+		options  []runtime.ServeMuxOption
+		header   string
+		code     codes.Code
+	}{
+		{"DefaultMatcherWithPrefix", nil, "Grpc-Metadata-X-Request-Id", codes.OK},
+		{"DefaultMatcherWithoutPrefix", nil, "X-Request-Id", codes.InvalidArgument},
+		{"CustomMatcher", []runtime.ServeMuxOption{runtime.WithIncomingHeaderMatcher(forwardAll)}, "X-Request-Id", codes.OK},
+	}
+	for _, trial := range gatewayTests {
+		t.Run(trial.testname, func(tt *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/books", nil)
+			request.Header.Set(trial.header, "1")
+			request.Header.Set("Cookie", "session=abc")
+			ctx, err := runtime.AnnotateIncomingContext(context.Background(), runtime.NewServeMux(trial.options...), request)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			md, _ := metadata.FromIncomingContext(ctx)
+			values, err := ExtractMetadata(md, contract)
+			if status.Code(err) != trial.code {
+				tt.Fatalf("Expected the code %s, got %s", trial.code, status.Code(err))
+			}
+			expected := map[string]string{"X-Request-Id": "1", "session": "abc"}
+			if diff := cmp.Diff(expected, values); err == nil && diff != "" {
+				tt.Errorf("ExtractMetadata: diff(-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// testServerStream is a grpc.ServerStream with the context 'ctx'.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	// Registers a server-streaming method with a (gnostic.grpc.metadata) option.
+	options := &descriptorpb.MethodOptions{}
+	proto.SetExtension(options, E_Metadata, []*Metadata{
+		{Name: "X-Request-Id", Source: Metadata_HEADER, Type: "string", Required: true},
+	})
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("transcoding_test/streaming.proto"),
+		Package:     proto.String("transcoding_test"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Book")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Bookstore"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:            proto.String("WatchBooks"),
+				InputType:       proto.String(".transcoding_test.Book"),
+				OutputType:      proto.String(".transcoding_test.Book"),
+				ServerStreaming: proto.Bool(true),
+				Options:         options,
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	interceptor := StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/transcoding_test.Bookstore/WatchBooks", IsServerStream: true}
+	var values map[string]string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		values = MetadataValues(stream.Context())
+		return nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "1"))
+	if err := interceptor(nil, &testServerStream{ctx: ctx}, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"X-Request-Id": "1"}, values); diff != "" {
+		t.Errorf("MetadataValues: diff(-want +got):\n%s", diff)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.MD{})
+	err = interceptor(nil, &testServerStream{ctx: ctx}, info, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected the code %s for a missing header, got %s", codes.InvalidArgument, status.Code(err))
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: transcoding/options.proto
+
+package transcoding
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Where the value is sent in the HTTP request.
+type Metadata_Source int32
+
+const (
+	Metadata_HEADER Metadata_Source = 0
+	Metadata_COOKIE Metadata_Source = 1
+)
+
+// Enum value maps for Metadata_Source.
+var (
+	Metadata_Source_name = map[int32]string{
+		0: "HEADER",
+		1: "COOKIE",
+	}
+	Metadata_Source_value = map[string]int32{
+		"HEADER": 0,
+		"COOKIE": 1,
+	}
+)
+
+func (x Metadata_Source) Enum() *Metadata_Source {
+	p := new(Metadata_Source)
+	*p = x
+	return p
+}
+
+func (x Metadata_Source) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Metadata_Source) Descriptor() protoreflect.EnumDescriptor {
+	return file_transcoding_options_proto_enumTypes[0].Descriptor()
+}
+
+func (Metadata_Source) Type() protoreflect.EnumType {
+	return &file_transcoding_options_proto_enumTypes[0]
+}
+
+func (x Metadata_Source) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Metadata_Source.Descriptor instead.
+func (Metadata_Source) EnumDescriptor() ([]byte, []int) {
+	return file_transcoding_options_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Metadata is a header or cookie parameter of an operation. Transcoders don't populate request fields from headers,
+// so those parameters are passed as gRPC metadata instead.
+type Metadata struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the header or cookie, e.g.: "X-Request-Id".
+	Name   string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source Metadata_Source `protobuf:"varint,2,opt,name=source,proto3,enum=gnostic.grpc.Metadata_Source" json:"source,omitempty"`
+	// The OpenAPI type of the value: "string", "integer", "number" or "boolean".
+	Type     string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Required bool   `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transcoding_options_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_transcoding_options_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_transcoding_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Metadata) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metadata) GetSource() Metadata_Source {
+	if x != nil {
+		return x.Source
+	}
+	return Metadata_HEADER
+}
+
+func (x *Metadata) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Metadata) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+var file_transcoding_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: ([]*Metadata)(nil),
+		Field:         51105,
+		Name:          "gnostic.grpc.metadata",
+		Tag:           "bytes,51105,rep,name=metadata",
+		Filename:      "transcoding/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// The metadata the RPC expects, see transcoding.UnaryServerInterceptor. The number is in the range 50000-99999,
+	// which descriptor.proto reserves for use within individual organizations, because the option isn't registered in
+	// the global extension registry. Other options of the same number can't be imported next to it.
+	//
+	// repeated gnostic.grpc.Metadata metadata = 51105;
+	E_Metadata = &file_transcoding_options_proto_extTypes[0]
+)
+
+var File_transcoding_options_proto protoreflect.FileDescriptor
+
+var file_transcoding_options_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x2f, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x67, 0x6e, 0x6f,
+	0x73, 0x74, 0x69, 0x63, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa7, 0x01, 0x0a, 0x08,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x35, 0x0a, 0x06,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x67,
+	0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x2e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x06, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x22, 0x20, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x0a, 0x0a,
+	0x06, 0x48, 0x45, 0x41, 0x44, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4f,
+	0x4b, 0x49, 0x45, 0x10, 0x01, 0x3a, 0x54, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0xa1, 0x8f, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x42, 0x38, 0x5a, 0x36, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2d, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x63, 0x6f, 0x64, 0x69, 0x6e, 0x67, 0x3b, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x63,
+	0x6f, 0x64, 0x69, 0x6e, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_transcoding_options_proto_rawDescOnce sync.Once
+	file_transcoding_options_proto_rawDescData = file_transcoding_options_proto_rawDesc
+)
+
+func file_transcoding_options_proto_rawDescGZIP() []byte {
+	file_transcoding_options_proto_rawDescOnce.Do(func() {
+		file_transcoding_options_proto_rawDescData = protoimpl.X.CompressGZIP(file_transcoding_options_proto_rawDescData)
+	})
+	return file_transcoding_options_proto_rawDescData
+}
+
+var file_transcoding_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_transcoding_options_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_transcoding_options_proto_goTypes = []interface{}{
+	(Metadata_Source)(0),               // 0: gnostic.grpc.Metadata.Source
+	(*Metadata)(nil),                   // 1: gnostic.grpc.Metadata
+	(*descriptorpb.MethodOptions)(nil), // 2: google.protobuf.MethodOptions
+}
+var file_transcoding_options_proto_depIdxs = []int32{
+	0, // 0: gnostic.grpc.Metadata.source:type_name -> gnostic.grpc.Metadata.Source
+	2, // 1: gnostic.grpc.metadata:extendee -> google.protobuf.MethodOptions
+	1, // 2: gnostic.grpc.metadata:type_name -> gnostic.grpc.Metadata
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	1, // [1:2] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_transcoding_options_proto_init() }
+func file_transcoding_options_proto_init() {
+	if File_transcoding_options_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_transcoding_options_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Metadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_transcoding_options_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_transcoding_options_proto_goTypes,
+		DependencyIndexes: file_transcoding_options_proto_depIdxs,
+		EnumInfos:         file_transcoding_options_proto_enumTypes,
+		MessageInfos:      file_transcoding_options_proto_msgTypes,
+		ExtensionInfos:    file_transcoding_options_proto_extTypes,
+	}.Build()
+	File_transcoding_options_proto = out.File
+	file_transcoding_options_proto_rawDesc = nil
+	file_transcoding_options_proto_goTypes = nil
+	file_transcoding_options_proto_depIdxs = nil
+}
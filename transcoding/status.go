@@ -0,0 +1,104 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transcoding contains helpers for gRPC servers that implement the services generated by gnostic-grpc and are
+// called through gRPC HTTP/JSON transcoding.
+package transcoding
+
+import (
+	"net/http"
+
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// HTTPStatusCodes maps gRPC codes onto HTTP status codes, as documented in google/rpc/code.proto. Transcoders use the
+// same mapping to answer HTTP requests.
+// See: https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+var HTTPStatusCodes = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// GrpcCodes maps HTTP status codes onto the gRPC code whose HTTP status code is the closest match. It is the inverse
+// of HTTPStatusCodes for the status codes used by more than one gRPC code (400, 409 and 500) and adds status codes
+// that have no exact gRPC counterpart (e.g. 412 and 416).
+var GrpcCodes = map[int]codes.Code{
+	http.StatusOK:                           codes.OK,
+	http.StatusBadRequest:                   codes.InvalidArgument,
+	http.StatusUnauthorized:                 codes.Unauthenticated,
+	http.StatusForbidden:                    codes.PermissionDenied,
+	http.StatusNotFound:                     codes.NotFound,
+	http.StatusConflict:                     codes.Aborted,
+	http.StatusPreconditionFailed:           codes.FailedPrecondition,
+	http.StatusRequestedRangeNotSatisfiable: codes.OutOfRange,
+	http.StatusTooManyRequests:              codes.ResourceExhausted,
+	499:                                     codes.Canceled,
+	http.StatusInternalServerError:          codes.Internal,
+	http.StatusNotImplemented:               codes.Unimplemented,
+	http.StatusServiceUnavailable:           codes.Unavailable,
+	http.StatusGatewayTimeout:               codes.DeadlineExceeded,
+}
+
+// CodeFromHTTPStatus returns the gRPC code for the HTTP status code 'httpStatus'. Successful status codes map onto
+// codes.OK, status codes without a counterpart onto codes.Unknown.
+func CodeFromHTTPStatus(httpStatus int) codes.Code {
+	if code, ok := GrpcCodes[httpStatus]; ok {
+		return code
+	}
+	if httpStatus >= 200 && httpStatus < 300 {
+		return codes.OK
+	}
+	return codes.Unknown
+}
+
+// HTTPStatusFromCode returns the HTTP status code for the gRPC code 'code'.
+func HTTPStatusFromCode(code codes.Code) int {
+	if httpStatus, ok := HTTPStatusCodes[code]; ok {
+		return httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// NewStatus returns the status a server returns for the error response 'httpStatus' of an operation. The messages
+// generated for the error schemas of the operation are passed as 'details' and end up in google.rpc.Status.details.
+func NewStatus(httpStatus int, message string, details ...proto.Message) (*status.Status, error) {
+	s := &spb.Status{Code: int32(CodeFromHTTPStatus(httpStatus)), Message: message}
+	for _, detail := range details {
+		any, err := anypb.New(detail)
+		if err != nil {
+			return nil, err
+		}
+		s.Details = append(s.Details, any)
+	}
+	return status.FromProto(s), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcoding
+
+import (
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	var codeTests = []struct {
+		httpStatus int
+		expected   codes.Code
+	}{
+		{200, codes.OK},
+		{204, codes.OK},
+		{400, codes.InvalidArgument},
+		{404, codes.NotFound},
+		{409, codes.Aborted},
+		{418, codes.Unknown},
+		{503, codes.Unavailable},
+	}
+	for _, trial := range codeTests {
+		if got := CodeFromHTTPStatus(trial.httpStatus); got != trial.expected {
+			t.Errorf("CodeFromHTTPStatus(%d) = %s, want %s", trial.httpStatus, got, trial.expected)
+		}
+	}
+}
+
+func TestHTTPStatusRoundTrip(t *testing.T) {
+	for httpStatus, code := range GrpcCodes {
+		if got := HTTPStatusFromCode(code); got != httpStatus && !(httpStatus == 412 || httpStatus == 416) {
+			t.Errorf("HTTPStatusFromCode(%s) = %d, want %d", code, got, httpStatus)
+		}
+	}
+}
+
+func TestNewStatus(t *testing.T) {
+	s, err := NewStatus(404, "book not found", &errdetails.ResourceInfo{ResourceName: "books/1"})
+	if err != nil {
+		t.Fatalf("NewStatus returned an error: %s", err)
+	}
+	if s.Code() != codes.NotFound {
+		t.Errorf("Expected the code NotFound, got %s", s.Code())
+	}
+	if len(s.Details()) != 1 {
+		t.Errorf("Expected one detail, got %d", len(s.Details()))
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcoding
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// The name of the FieldMask field of PATCH requests, which is generated with the parameter patch=update_mask.
+const updateMaskField = "update_mask"
+
+// UpdateMask returns the FieldMask of the fields of 'message' that are present in the JSON object 'body', e.g.:
+// "name" and "options.deprecated" for {"name": "x", "options": {"deprecated": true}}. Keys are matched against the
+// JSON names and the names of the fields, nested objects become dotted paths. Well-known types, repeated fields and
+// maps are replaced as a whole. Unknown keys are ignored.
+func UpdateMask(body []byte, message protoreflect.MessageDescriptor) (*fieldmaskpb.FieldMask, error) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("the body isn't a JSON object: %v", err)
+	}
+	mask := &fieldmaskpb.FieldMask{}
+	appendMaskPaths(mask, "", object, message)
+	return mask, nil
+}
+
+// SetUpdateMask sets the update_mask field of 'request' to the mask of the JSON object 'body', which has been
+// transcoded into the field 'bodyField' of 'request'. A mask that has been sent by the client is kept. Transcoders
+// that don't forward the HTTP body can call it while decoding the request, e.g. in a grpc-gateway marshaler.
+func SetUpdateMask(request proto.Message, bodyField string, body []byte) error {
+	message := request.ProtoReflect()
+	fields := message.Descriptor().Fields()
+	maskField, resourceField := fields.ByName(updateMaskField), fields.ByName(protoreflect.Name(bodyField))
+	if maskField == nil || maskField.Message() == nil || maskField.Message().FullName() != "google.protobuf.FieldMask" {
+		return fmt.Errorf("%s has no field %s of type google.protobuf.FieldMask", message.Descriptor().FullName(), updateMaskField)
+	}
+	if resourceField == nil || resourceField.Message() == nil {
+		return fmt.Errorf("%s has no message field %s", message.Descriptor().FullName(), bodyField)
+	}
+	if message.Has(maskField) && len(message.Get(maskField).Message().Interface().(*fieldmaskpb.FieldMask).GetPaths()) > 0 {
+		return nil
+	}
+	mask, err := UpdateMask(body, resourceField.Message())
+	if err != nil {
+		return err
+	}
+	message.Set(maskField, protoreflect.ValueOfMessage(mask.ProtoReflect()))
+	return nil
+}
+
+func appendMaskPaths(mask *fieldmaskpb.FieldMask, prefix string, object map[string]json.RawMessage, message protoreflect.MessageDescriptor) {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field := message.Fields().ByJSONName(key)
+		if field == nil {
+			field = message.Fields().ByName(protoreflect.Name(key))
+		}
+		if field == nil {
+			continue
+		}
+		path := prefix + string(field.Name())
+		var nested map[string]json.RawMessage
+		if isNestedMessage(field) && json.Unmarshal(object[key], &nested) == nil && len(nested) > 0 {
+			appendMaskPaths(mask, path+".", nested, field.Message())
+			continue
+		}
+		mask.Paths = append(mask.Paths, path)
+	}
+}
+
+// isNestedMessage returns true if 'field' is a singular message whose fields can be updated individually. Messages
+// of the well-known types with a special JSON representation (e.g. google.protobuf.Timestamp) are values.
+func isNestedMessage(field protoreflect.FieldDescriptor) bool {
+	if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+		return false
+	}
+	file := field.Message().ParentFile().Path()
+	return !strings.HasPrefix(file, "google/protobuf/") || file == "google/protobuf/descriptor.proto"
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transcoding
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestUpdateMask(t *testing.T) {
+	var maskTests = []struct {
+		testname string
+		body     string
+		expected []string
+	}{
+		{"JSONNames", `{"name": "id", "typeName": ".acme.Book"}`, []string{"name", "type_name"}},
+		{"FieldNames", `{"json_name": "id"}`, []string{"json_name"}},
+		{"NestedMessage", `{"options": {"deprecated": true, "lazy": false}}`, []string{"options.deprecated", "options.lazy"}},
+		{"EmptyNestedMessage", `{"options": {}}`, []string{"options"}},
+		{"ClearedNestedMessage", `{"options": null}`, []string{"options"}},
+		{"UnknownKeys", `{"color": "red"}`, nil},
+	}
+	message := (&descriptorpb.FieldDescriptorProto{}).ProtoReflect().Descriptor()
+	for _, trial := range maskTests {
+		t.Run(trial.testname, func(tt *testing.T) {
+			mask, err := UpdateMask([]byte(trial.body), message)
+			if err != nil {
+				tt.Fatalf("UpdateMask returned an error: %s", err)
+			}
+			if diff := cmp.Diff(trial.expected, mask.GetPaths()); diff != "" {
+				tt.Errorf("UpdateMask: diff(-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := UpdateMask([]byte(`["name"]`), message); err == nil {
+		t.Errorf("Expected an error for a body that isn't a JSON object")
+	}
+}
+
+func TestSetUpdateMaskWithoutMaskField(t *testing.T) {
+	err := SetUpdateMask(&descriptorpb.FieldDescriptorProto{}, "options", []byte(`{"name": "id"}`))
+	if err == nil {
+		t.Errorf("Expected an error for a request without update_mask")
+	}
+}
+
+// newUpdateRequest returns an empty request message with the fields "field" of type google.protobuf.FieldDescriptorProto
+// and "update_mask" of type google.protobuf.FieldMask.
+func newUpdateRequest(t *testing.T) *dynamicpb.Message {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("transcoding_test/updatemask.proto"),
+		Package:    proto.String("transcoding_test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/descriptor.proto", "google/protobuf/field_mask.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("UpdateFieldRequest"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     proto.String("field"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".google.protobuf.FieldDescriptorProto"),
+					JsonName: proto.String("field"),
+				},
+				{
+					Name:     proto.String("update_mask"),
+					Number:   proto.Int32(2),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".google.protobuf.FieldMask"),
+					JsonName: proto.String("updateMask"),
+				},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessage(file.Messages().ByName("UpdateFieldRequest"))
+}
+
+// updateMaskPaths returns the paths of the update_mask of 'request'.
+func updateMaskPaths(request *dynamicpb.Message) []string {
+	field := request.Descriptor().Fields().ByName(updateMaskField)
+	mask := &fieldmaskpb.FieldMask{}
+	proto.Merge(mask, request.Get(field).Message().Interface())
+	return mask.GetPaths()
+}
+
+func TestSetUpdateMask(t *testing.T) {
+	request := newUpdateRequest(t)
+	if err := SetUpdateMask(request, "field", []byte(`{"name": "id", "options": {"deprecated": true}}`)); err != nil {
+		t.Fatalf("SetUpdateMask returned an error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"name", "options.deprecated"}, updateMaskPaths(request)); diff != "" {
+		t.Errorf("SetUpdateMask: diff(-want +got):\n%s", diff)
+	}
+}
+
+func TestSetUpdateMaskKeepsMaskOfClient(t *testing.T) {
+	request := newUpdateRequest(t)
+	field := request.Descriptor().Fields().ByName(updateMaskField)
+	request.Set(field, protoreflect.ValueOfMessage((&fieldmaskpb.FieldMask{Paths: []string{"type_name"}}).ProtoReflect()))
+	if err := SetUpdateMask(request, "field", []byte(`{"name": "id"}`)); err != nil {
+		t.Fatalf("SetUpdateMask returned an error: %s", err)
+	}
+	if diff := cmp.Diff([]string{"type_name"}, updateMaskPaths(request)); diff != "" {
+		t.Errorf("SetUpdateMask: diff(-want +got):\n%s", diff)
+	}
+}
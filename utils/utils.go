@@ -16,6 +16,7 @@ package utils
 
 import (
 	"os/exec"
+	"strings"
 
 	openapiv3 "github.com/google/gnostic/openapiv3"
 	"google.golang.org/protobuf/encoding/prototext"
@@ -68,3 +69,85 @@ func Contains(ss []string, s string) bool {
 	}
 	return false
 }
+
+// IsFormMediaType returns true for the media types of HTML forms, e.g.: "multipart/form-data; boundary=x". gRPC
+// HTTP/JSON transcoding can't decode forms, so form bodies are passed through as google.api.HttpBody.
+func IsFormMediaType(mediaType string) bool {
+	mediaType = strings.TrimSpace(strings.Split(mediaType, ";")[0])
+	return strings.EqualFold(mediaType, "application/x-www-form-urlencoded") ||
+		strings.EqualFold(mediaType, "multipart/form-data")
+}
+
+// ResolveSchema returns the schema of 'schema', which may reference a schema of the components of 'document'. nil is
+// returned if the reference can't be resolved.
+func ResolveSchema(document *openapiv3.Document, schema *openapiv3.SchemaOrReference) *openapiv3.Schema {
+	reference := schema.GetReference()
+	if reference == nil {
+		return schema.GetSchema()
+	}
+	for _, named := range document.GetComponents().GetSchemas().GetAdditionalProperties() {
+		if reference.XRef == "#/components/schemas/"+named.Name {
+			return named.GetValue().GetSchema()
+		}
+	}
+	return nil
+}
+
+// ResolveParameter returns the parameter of 'parameterOrReference' and the name of its request field. The field of a
+// referenced parameter is named after the component.
+func ResolveParameter(document *openapiv3.Document, parameterOrReference *openapiv3.ParameterOrReference) (*openapiv3.Parameter, string) {
+	reference := parameterOrReference.GetReference()
+	if reference == nil {
+		return parameterOrReference.GetParameter(), parameterOrReference.GetParameter().GetName()
+	}
+	for _, named := range document.GetComponents().GetParameters().GetAdditionalProperties() {
+		if reference.XRef == "#/components/parameters/"+named.Name {
+			return named.GetValue().GetParameter(), named.Name
+		}
+	}
+	return nil, ""
+}
+
+// IsExplodedQueryObject returns true if 'parameter' is an exploded form object, whose properties are sent as top-level
+// query parameters.
+func IsExplodedQueryObject(document *openapiv3.Document, parameter *openapiv3.Parameter) bool {
+	if parameter.In != "query" || (parameter.Style != "" && parameter.Style != "form") || !parameter.Explode {
+		return false
+	}
+	schema := ResolveSchema(document, parameter.GetSchema())
+	return schema.GetType() == "object" || len(schema.GetProperties().GetAdditionalProperties()) > 0
+}
+
+// IsFlattenedQueryObject returns true if 'parameter' of 'operation' is an exploded form object that can be flattened
+// into the request message. The properties must be scalars or arrays of scalars and their names must not collide with
+// other parameters of 'operation', which may be nil.
+func IsFlattenedQueryObject(document *openapiv3.Document, operation *openapiv3.Operation, parameter *openapiv3.Parameter) bool {
+	if !IsExplodedQueryObject(document, parameter) {
+		return false
+	}
+	properties := ResolveSchema(document, parameter.GetSchema()).GetProperties().GetAdditionalProperties()
+	for _, property := range properties {
+		if !isScalarQueryValue(property.Value) {
+			return false
+		}
+		for _, other := range operation.GetParameters() {
+			if p, _ := ResolveParameter(document, other); p.GetName() == property.Name {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isScalarQueryValue returns true if 'schemaOrReference' is a scalar or an array of scalars.
+func isScalarQueryValue(schemaOrReference *openapiv3.SchemaOrReference) bool {
+	schema := schemaOrReference.GetSchema()
+	switch schema.GetType() {
+	case "string", "integer", "number", "boolean":
+		return true
+	case "array":
+		items := schema.GetItems().GetSchemaOrReference()
+		return len(items) > 0 && items[0].GetReference() == nil && isScalarQueryValue(items[0])
+	}
+	return false
+}